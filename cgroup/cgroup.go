@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: BSD-3-Clause
+package cgroup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/shirou/gopsutil/v4/load"
+)
+
+var ErrCgroupNotAvailable = errors.New("cgroup not available")
+
+// CPUStat is the CPU accounting of a cgroup. Times are in seconds.
+type CPUStat struct {
+	Usage         float64 `json:"usage"`
+	User          float64 `json:"user"`
+	System        float64 `json:"system"`
+	NrPeriods     uint64  `json:"nrPeriods"`
+	NrThrottled   uint64  `json:"nrThrottled"`
+	ThrottledTime float64 `json:"throttledTime"`
+	// Limit is the CFS bandwidth limit as a number of CPUs, 0 means unlimited.
+	Limit float64 `json:"limit"`
+}
+
+func (c CPUStat) String() string {
+	s, _ := json.Marshal(c)
+	return string(s)
+}
+
+// MemoryStat is the memory accounting of a cgroup. Sizes are in bytes.
+// A Limit of math.MaxUint64 means unlimited.
+type MemoryStat struct {
+	Usage      uint64 `json:"usage"`
+	MaxUsage   uint64 `json:"maxUsage"`
+	Limit      uint64 `json:"limit"`
+	SwapUsage  uint64 `json:"swapUsage"`
+	Anon       uint64 `json:"anon"`
+	File       uint64 `json:"file"`
+	FileMapped uint64 `json:"fileMapped"`
+	Shmem      uint64 `json:"shmem"`
+	FailCnt    uint64 `json:"failcnt"`
+	OOMKill    uint64 `json:"oomKill"`
+	// Stat holds the raw content of memory.stat.
+	Stat map[string]uint64 `json:"stat"`
+}
+
+func (m MemoryStat) String() string {
+	s, _ := json.Marshal(m)
+	return string(s)
+}
+
+type IODeviceStat struct {
+	Major        uint64 `json:"major"`
+	Minor        uint64 `json:"minor"`
+	ReadBytes    uint64 `json:"readBytes"`
+	WriteBytes   uint64 `json:"writeBytes"`
+	ReadIOs      uint64 `json:"readIOs"`
+	WriteIOs     uint64 `json:"writeIOs"`
+	DiscardBytes uint64 `json:"discardBytes"`
+	DiscardIOs   uint64 `json:"discardIOs"`
+}
+
+type IOStat struct {
+	Devices []IODeviceStat `json:"devices"`
+}
+
+func (i IOStat) String() string {
+	s, _ := json.Marshal(i)
+	return string(s)
+}
+
+// PidsStat is the number of tasks in a cgroup. A Limit of math.MaxUint64
+// means unlimited.
+type PidsStat struct {
+	Current uint64 `json:"current"`
+	Limit   uint64 `json:"limit"`
+}
+
+func (p PidsStat) String() string {
+	s, _ := json.Marshal(p)
+	return string(s)
+}
+
+// PressureAvg and PressureStat are shared with the system wide Pressure
+// Stall Information of the load package.
+type (
+	PressureAvg  = load.PressureAvg
+	PressureStat = load.PressureStat
+)
+
+// Stat gathers all statistics of a cgroup. Controllers which are not
+// enabled for the cgroup are left nil.
+type Stat struct {
+	Path     string         `json:"path"`
+	CPU      *CPUStat       `json:"cpu"`
+	Memory   *MemoryStat    `json:"memory"`
+	IO       *IOStat        `json:"io"`
+	Pids     *PidsStat      `json:"pids"`
+	Pressure []PressureStat `json:"pressure"`
+}
+
+func (s Stat) String() string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// Membership is a line of /proc/<pid>/cgroup. On cgroup v2 HierarchyID is 0
+// and Controllers is empty.
+type Membership struct {
+	HierarchyID int      `json:"hierarchyID"`
+	Controllers []string `json:"controllers"`
+	Path        string   `json:"path"`
+}
+
+func (m Membership) String() string {
+	s, _ := json.Marshal(m)
+	return string(s)
+}
+
+// IsUnified returns true if the host uses the cgroup v2 unified hierarchy.
+func IsUnified() bool {
+	return IsUnifiedWithContext(context.Background())
+}
+
+// CPU returns the CPU statistics of the cgroup at path, relative to the
+// cgroup root, such as "/system.slice/nginx.service".
+func CPU(path string) (*CPUStat, error) {
+	return CPUWithContext(context.Background(), path)
+}
+
+// Memory returns the memory statistics of the cgroup at path.
+func Memory(path string) (*MemoryStat, error) {
+	return MemoryWithContext(context.Background(), path)
+}
+
+// IO returns the block I/O statistics of the cgroup at path.
+func IO(path string) (*IOStat, error) {
+	return IOWithContext(context.Background(), path)
+}
+
+// Pids returns the number of tasks of the cgroup at path.
+func Pids(path string) (*PidsStat, error) {
+	return PidsWithContext(context.Background(), path)
+}
+
+// Pressure returns the Pressure Stall Information of the cgroup at path.
+// This is only available on cgroup v2.
+func Pressure(path string) ([]PressureStat, error) {
+	return PressureWithContext(context.Background(), path)
+}
+
+// Get returns all available statistics of the cgroup at path.
+func Get(path string) (*Stat, error) {
+	return GetWithContext(context.Background(), path)
+}
+
+// GetByPid returns all available statistics of the cgroup the process
+// belongs to, resolved from /proc/<pid>/cgroup.
+func GetByPid(pid int32) (*Stat, error) {
+	return GetByPidWithContext(context.Background(), pid)
+}
+
+// PidCgroups returns the cgroups the process belongs to.
+func PidCgroups(pid int32) ([]Membership, error) {
+	return PidCgroupsWithContext(context.Background(), pid)
+}
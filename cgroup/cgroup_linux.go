@@ -0,0 +1,459 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//go:build linux
+
+package cgroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/internal/common"
+	"github.com/shirou/gopsutil/v4/load"
+)
+
+const (
+	nanoseconds  = 1e9
+	microseconds = 1e6
+)
+
+// hierarchy resolves the directory of a cgroup for each controller.
+// On cgroup v2 every controller shares the same directory. On cgroup v1
+// each controller has its own mount and the cgroup path may differ
+// between controllers, so paths is keyed by controller name and the ""
+// key is used as a fallback.
+type hierarchy struct {
+	unified bool
+	paths   map[string]string
+}
+
+func newHierarchyWithContext(ctx context.Context, path string) (hierarchy, error) {
+	if err := validatePath(path); err != nil {
+		return hierarchy{}, err
+	}
+	return hierarchy{
+		unified: IsUnifiedWithContext(ctx),
+		paths:   map[string]string{"": path},
+	}, nil
+}
+
+func (h hierarchy) path(controller string) string {
+	if p, ok := h.paths[controller]; ok && !h.unified {
+		return p
+	}
+	return h.paths[""]
+}
+
+func (h hierarchy) dir(ctx context.Context, controller string) string {
+	if h.unified {
+		return common.HostSysWithContext(ctx, "fs/cgroup", h.path(controller))
+	}
+	return common.HostSysWithContext(ctx, "fs/cgroup", controller, h.path(controller))
+}
+
+func (h hierarchy) file(ctx context.Context, controller, file string) string {
+	return filepath.Join(h.dir(ctx, controller), file)
+}
+
+// validatePath prevents a caller-supplied path from escaping the cgroup
+// mount via ".." traversal.
+func validatePath(path string) error {
+	for _, elem := range strings.Split(path, "/") {
+		if elem == ".." {
+			return fmt.Errorf("invalid cgroup path %q", path)
+		}
+	}
+	return nil
+}
+
+func IsUnifiedWithContext(ctx context.Context) bool {
+	return common.PathExists(common.HostSysWithContext(ctx, "fs/cgroup/cgroup.controllers"))
+}
+
+func CPUWithContext(ctx context.Context, path string) (*CPUStat, error) {
+	h, err := newHierarchyWithContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return h.cpuWithContext(ctx)
+}
+
+func MemoryWithContext(ctx context.Context, path string) (*MemoryStat, error) {
+	h, err := newHierarchyWithContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return h.memoryWithContext(ctx)
+}
+
+func IOWithContext(ctx context.Context, path string) (*IOStat, error) {
+	h, err := newHierarchyWithContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return h.ioWithContext(ctx)
+}
+
+func PidsWithContext(ctx context.Context, path string) (*PidsStat, error) {
+	h, err := newHierarchyWithContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return h.pidsWithContext(ctx)
+}
+
+func PressureWithContext(ctx context.Context, path string) ([]PressureStat, error) {
+	h, err := newHierarchyWithContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return h.pressureWithContext(ctx)
+}
+
+func GetWithContext(ctx context.Context, path string) (*Stat, error) {
+	h, err := newHierarchyWithContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return h.statWithContext(ctx)
+}
+
+func GetByPidWithContext(ctx context.Context, pid int32) (*Stat, error) {
+	cgroups, err := PidCgroupsWithContext(ctx, pid)
+	if err != nil {
+		return nil, err
+	}
+	h := hierarchy{
+		unified: IsUnifiedWithContext(ctx),
+		paths:   make(map[string]string, len(cgroups)),
+	}
+	for _, c := range cgroups {
+		if c.HierarchyID == 0 {
+			h.paths[""] = c.Path
+			continue
+		}
+		for _, controller := range c.Controllers {
+			h.paths[controller] = c.Path
+		}
+	}
+	if _, ok := h.paths[""]; !ok {
+		// pure cgroup v1 host, use the memory hierarchy as the reported path
+		h.paths[""] = h.paths["memory"]
+	}
+	return h.statWithContext(ctx)
+}
+
+func PidCgroupsWithContext(ctx context.Context, pid int32) ([]Membership, error) {
+	lines, err := common.ReadLines(common.HostProcWithContext(ctx, strconv.Itoa(int(pid)), "cgroup"))
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]Membership, 0, len(lines))
+	for _, line := range lines {
+		m, err := parseMembership(line)
+		if err != nil {
+			continue
+		}
+		ret = append(ret, m)
+	}
+	return ret, nil
+}
+
+// parseMembership parses a line of /proc/<pid>/cgroup, such as
+// "4:cpu,cpuacct:/docker/<id>" (v1) or "0::/system.slice/foo.service" (v2).
+func parseMembership(line string) (Membership, error) {
+	fields := strings.SplitN(line, ":", 3)
+	if len(fields) != 3 {
+		return Membership{}, fmt.Errorf("invalid cgroup line %q", line)
+	}
+	id, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return Membership{}, err
+	}
+	m := Membership{
+		HierarchyID: id,
+		Controllers: []string{},
+		Path:        fields[2],
+	}
+	if fields[1] != "" {
+		// named hierarchies such as "name=systemd" are kept as is
+		m.Controllers = strings.Split(fields[1], ",")
+	}
+	return m, nil
+}
+
+// statWithContext gathers all statistics, skipping controllers which are
+// not enabled for the cgroup.
+func (h hierarchy) statWithContext(ctx context.Context) (*Stat, error) {
+	ret := &Stat{Path: h.paths[""]}
+
+	cpuStat, err := h.cpuWithContext(ctx)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	ret.CPU = cpuStat
+
+	memStat, err := h.memoryWithContext(ctx)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	ret.Memory = memStat
+
+	ioStat, err := h.ioWithContext(ctx)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	ret.IO = ioStat
+
+	pidsStat, err := h.pidsWithContext(ctx)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	ret.Pids = pidsStat
+
+	pressure, err := h.pressureWithContext(ctx)
+	if err != nil && !errors.Is(err, ErrCgroupNotAvailable) {
+		return nil, err
+	}
+	ret.Pressure = pressure
+
+	if ret.CPU == nil && ret.Memory == nil && ret.IO == nil && ret.Pids == nil {
+		return nil, fmt.Errorf("could not read cgroup %q: %w", ret.Path, ErrCgroupNotAvailable)
+	}
+	return ret, nil
+}
+
+func (h hierarchy) cpuWithContext(ctx context.Context) (*CPUStat, error) {
+	if h.unified {
+		return h.cpuV2WithContext(ctx)
+	}
+	return h.cpuV1WithContext(ctx)
+}
+
+func (h hierarchy) cpuV2WithContext(ctx context.Context) (*CPUStat, error) {
+	stats, err := common.ReadCgroupFlatKeyed(h.file(ctx, "cpu", "cpu.stat"))
+	if err != nil {
+		return nil, err
+	}
+	ret := &CPUStat{
+		Usage:         float64(stats["usage_usec"]) / microseconds,
+		User:          float64(stats["user_usec"]) / microseconds,
+		System:        float64(stats["system_usec"]) / microseconds,
+		NrPeriods:     stats["nr_periods"],
+		NrThrottled:   stats["nr_throttled"],
+		ThrottledTime: float64(stats["throttled_usec"]) / microseconds,
+	}
+
+	// cpu.max is "$MAX $PERIOD", where $MAX may be "max". It does not
+	// exist on the root cgroup.
+	contents, err := os.ReadFile(h.file(ctx, "cpu", "cpu.max"))
+	if err == nil {
+		fields := strings.Fields(string(contents))
+		if len(fields) == 2 && fields[0] != "max" {
+			quota, err1 := strconv.ParseFloat(fields[0], 64)
+			period, err2 := strconv.ParseFloat(fields[1], 64)
+			if err1 == nil && err2 == nil && period > 0 {
+				ret.Limit = quota / period
+			}
+		}
+	}
+	return ret, nil
+}
+
+func (h hierarchy) cpuV1WithContext(ctx context.Context) (*CPUStat, error) {
+	usage, err := readUint(h.file(ctx, "cpuacct", "cpuacct.usage"))
+	if err != nil {
+		return nil, err
+	}
+	ret := &CPUStat{
+		Usage: float64(usage) / nanoseconds,
+	}
+
+	stats, err := common.ReadCgroupFlatKeyed(h.file(ctx, "cpuacct", "cpuacct.stat"))
+	if err != nil {
+		return nil, err
+	}
+	ret.User = float64(stats["user"]) / cpu.ClocksPerSec
+	ret.System = float64(stats["system"]) / cpu.ClocksPerSec
+
+	// the cpu controller may be mounted separately from cpuacct
+	stats, err = common.ReadCgroupFlatKeyed(h.file(ctx, "cpu", "cpu.stat"))
+	if err == nil {
+		ret.NrPeriods = stats["nr_periods"]
+		ret.NrThrottled = stats["nr_throttled"]
+		ret.ThrottledTime = float64(stats["throttled_time"]) / nanoseconds
+	}
+	quota, err1 := readInt(h.file(ctx, "cpu", "cpu.cfs_quota_us"))
+	period, err2 := readInt(h.file(ctx, "cpu", "cpu.cfs_period_us"))
+	if err1 == nil && err2 == nil && quota > 0 && period > 0 {
+		ret.Limit = float64(quota) / float64(period)
+	}
+	return ret, nil
+}
+
+func (h hierarchy) memoryWithContext(ctx context.Context) (*MemoryStat, error) {
+	stats, err := common.ReadCgroupFlatKeyed(h.file(ctx, "memory", "memory.stat"))
+	if err != nil {
+		return nil, err
+	}
+	ret := &MemoryStat{Stat: stats}
+
+	if h.unified {
+		ret.Anon = stats["anon"]
+		ret.File = stats["file"]
+		ret.FileMapped = stats["file_mapped"]
+		ret.Shmem = stats["shmem"]
+
+		if v, err := readUint(h.file(ctx, "memory", "memory.current")); err == nil {
+			ret.Usage = v
+		}
+		// memory.peak requires kernel >= 5.19
+		if v, err := readUint(h.file(ctx, "memory", "memory.peak")); err == nil {
+			ret.MaxUsage = v
+		}
+		if v, err := readUint(h.file(ctx, "memory", "memory.max")); err == nil {
+			ret.Limit = v
+		} else {
+			// the root cgroup has no memory.max
+			ret.Limit = math.MaxUint64
+		}
+		if v, err := readUint(h.file(ctx, "memory", "memory.swap.current")); err == nil {
+			ret.SwapUsage = v
+		}
+		if events, err := common.ReadCgroupFlatKeyed(h.file(ctx, "memory", "memory.events")); err == nil {
+			// v2 has no failcnt, the closest equivalent is the number of
+			// times the usage hit memory.max.
+			ret.FailCnt = events["max"]
+			ret.OOMKill = events["oom_kill"]
+		}
+		return ret, nil
+	}
+
+	// prefer the hierarchical counters, which match v2 semantics
+	ret.Anon = firstOf(stats, "total_rss", "rss")
+	ret.File = firstOf(stats, "total_cache", "cache")
+	ret.FileMapped = firstOf(stats, "total_mapped_file", "mapped_file")
+	ret.Shmem = firstOf(stats, "total_shmem", "shmem")
+
+	if v, err := readUint(h.file(ctx, "memory", "memory.usage_in_bytes")); err == nil {
+		ret.Usage = v
+	}
+	if v, err := readUint(h.file(ctx, "memory", "memory.max_usage_in_bytes")); err == nil {
+		ret.MaxUsage = v
+	}
+	if v, err := readUint(h.file(ctx, "memory", "memory.limit_in_bytes")); err == nil {
+		ret.Limit = v
+	}
+	// memory.memsw.* exist only when swap accounting is enabled
+	if v, err := readUint(h.file(ctx, "memory", "memory.memsw.usage_in_bytes")); err == nil && v > ret.Usage {
+		ret.SwapUsage = v - ret.Usage
+	}
+	if v, err := readUint(h.file(ctx, "memory", "memory.failcnt")); err == nil {
+		ret.FailCnt = v
+	}
+	if oom, err := common.ReadCgroupFlatKeyed(h.file(ctx, "memory", "memory.oom_control")); err == nil {
+		ret.OOMKill = oom["oom_kill"]
+	}
+	return ret, nil
+}
+
+func (h hierarchy) ioWithContext(ctx context.Context) (*IOStat, error) {
+	var devices []common.CgroupIODevice
+	var err error
+	if h.unified {
+		devices, err = common.ReadCgroupIOStat(h.file(ctx, "io", "io.stat"))
+	} else {
+		devices, err = common.ReadCgroupBlkio(
+			h.file(ctx, "blkio", "blkio.throttle.io_service_bytes"),
+			h.file(ctx, "blkio", "blkio.throttle.io_serviced"))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &IOStat{
+		Devices: make([]IODeviceStat, 0, len(devices)),
+	}
+	for _, d := range devices {
+		ret.Devices = append(ret.Devices, IODeviceStat(d))
+	}
+	return ret, nil
+}
+
+func (h hierarchy) pidsWithContext(ctx context.Context) (*PidsStat, error) {
+	current, err := readUint(h.file(ctx, "pids", "pids.current"))
+	if err != nil {
+		return nil, err
+	}
+	ret := &PidsStat{
+		Current: current,
+		Limit:   math.MaxUint64,
+	}
+	// the root cgroup has no pids.max
+	if v, err := readUint(h.file(ctx, "pids", "pids.max")); err == nil {
+		ret.Limit = v
+	}
+	return ret, nil
+}
+
+var pressureResources = []string{"cpu", "memory", "io", "irq"}
+
+func (h hierarchy) pressureWithContext(ctx context.Context) ([]PressureStat, error) {
+	if !h.unified {
+		return nil, fmt.Errorf("pressure stall information requires cgroup v2: %w", ErrCgroupNotAvailable)
+	}
+	ret := make([]PressureStat, 0, len(pressureResources))
+	for _, resource := range pressureResources {
+		stat, err := load.PressureByFileWithContext(ctx, resource, h.file(ctx, resource, resource+".pressure"))
+		if err != nil {
+			// irq.pressure requires kernel >= 6.1 and CONFIG_IRQ_TIME_ACCOUNTING,
+			// and PSI may be disabled entirely with psi=0.
+			if os.IsNotExist(err) || errors.Is(err, syscall.EOPNOTSUPP) {
+				continue
+			}
+			return nil, err
+		}
+		ret = append(ret, *stat)
+	}
+	if len(ret) == 0 {
+		return nil, fmt.Errorf("no pressure stall information found: %w", ErrCgroupNotAvailable)
+	}
+	return ret, nil
+}
+
+// readUint reads a single value cgroup file. "max" is returned as math.MaxUint64.
+func readUint(filename string) (uint64, error) {
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return 0, err
+	}
+	value := strings.TrimSpace(string(contents))
+	if value == "max" {
+		return math.MaxUint64, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+func readInt(filename string) (int64, error) {
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(contents)), 10, 64)
+}
+
+func firstOf(stats map[string]uint64, keys ...string) uint64 {
+	for _, key := range keys {
+		if v, ok := stats[key]; ok {
+			return v
+		}
+	}
+	return 0
+}
@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//go:build linux
+
+package cgroup
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+)
+
+const testPath = "/system.slice/nginx.service"
+
+func TestCPU(t *testing.T) {
+	t.Run("v1", func(t *testing.T) {
+		t.Setenv("HOST_SYS", "testdata/linux/v1/sys")
+
+		v, err := CPU(testPath)
+		require.NoError(t, err)
+		assert.InDelta(t, 37.5, v.Usage, 0.0001)
+		assert.InDelta(t, 2500/cpu.ClocksPerSec, v.User, 0.0001)
+		assert.InDelta(t, 1200/cpu.ClocksPerSec, v.System, 0.0001)
+		assert.Equal(t, uint64(7), v.NrThrottled)
+		assert.InDelta(t, 0.35, v.ThrottledTime, 0.0001)
+		assert.InDelta(t, 0.5, v.Limit, 0.0001)
+	})
+	t.Run("v2", func(t *testing.T) {
+		t.Setenv("HOST_SYS", "testdata/linux/v2/sys")
+
+		v, err := CPU(testPath)
+		require.NoError(t, err)
+		assert.InDelta(t, 37.5, v.Usage, 0.0001)
+		assert.InDelta(t, 25.0, v.User, 0.0001)
+		assert.InDelta(t, 12.5, v.System, 0.0001)
+		assert.Equal(t, uint64(7), v.NrThrottled)
+		assert.InDelta(t, 0.35, v.ThrottledTime, 0.0001)
+		assert.InDelta(t, 2.0, v.Limit, 0.0001)
+	})
+}
+
+func TestMemory(t *testing.T) {
+	for _, hostSys := range []string{"testdata/linux/v1/sys", "testdata/linux/v2/sys"} {
+		t.Run(hostSys, func(t *testing.T) {
+			t.Setenv("HOST_SYS", hostSys)
+
+			v, err := Memory(testPath)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(12288000), v.Usage)
+			assert.Equal(t, uint64(16384000), v.MaxUsage)
+			assert.Equal(t, uint64(536870912), v.Limit)
+			assert.Equal(t, uint64(8192000), v.Anon)
+			assert.Equal(t, uint64(4096000), v.File)
+			assert.Equal(t, uint64(1024000), v.FileMapped)
+			assert.Equal(t, uint64(8192), v.Shmem)
+			assert.Equal(t, uint64(3), v.FailCnt)
+			assert.Equal(t, uint64(1), v.OOMKill)
+			assert.Equal(t, uint64(12000), v.Stat["pgfault"])
+		})
+	}
+}
+
+func TestIO(t *testing.T) {
+	for _, hostSys := range []string{"testdata/linux/v1/sys", "testdata/linux/v2/sys"} {
+		t.Run(hostSys, func(t *testing.T) {
+			t.Setenv("HOST_SYS", hostSys)
+
+			v, err := IO(testPath)
+			require.NoError(t, err)
+			require.NotEmpty(t, v.Devices)
+			d := v.Devices[0]
+			assert.Equal(t, uint64(8), d.Major)
+			assert.Equal(t, uint64(0), d.Minor)
+			assert.Equal(t, uint64(1048576), d.ReadBytes)
+			assert.Equal(t, uint64(2097152), d.WriteBytes)
+			assert.Equal(t, uint64(256), d.ReadIOs)
+			assert.Equal(t, uint64(512), d.WriteIOs)
+		})
+	}
+}
+
+func TestPids(t *testing.T) {
+	t.Setenv("HOST_SYS", "testdata/linux/v1/sys")
+	v, err := Pids(testPath)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(12), v.Current)
+	assert.Equal(t, uint64(math.MaxUint64), v.Limit)
+
+	t.Setenv("HOST_SYS", "testdata/linux/v2/sys")
+	v, err = Pids(testPath)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(12), v.Current)
+	assert.Equal(t, uint64(4915), v.Limit)
+}
+
+func TestPressure(t *testing.T) {
+	t.Setenv("HOST_SYS", "testdata/linux/v1/sys")
+	_, err := Pressure(testPath)
+	require.ErrorIs(t, err, ErrCgroupNotAvailable)
+
+	t.Setenv("HOST_SYS", "testdata/linux/v2/sys")
+	v, err := Pressure(testPath)
+	require.NoError(t, err)
+	require.Len(t, v, 3)
+	assert.Equal(t, "cpu", v[0].Resource)
+	assert.InDelta(t, 1.5, v[0].Some.Avg10, 0.0001)
+	assert.InDelta(t, 0.25, v[0].Some.Avg300, 0.0001)
+	assert.Equal(t, uint64(123456), v[0].Some.Total)
+	assert.InDelta(t, 0.1, v[0].Full.Avg300, 0.0001)
+	assert.Equal(t, uint64(654321), v[0].Full.Total)
+}
+
+func TestGetByPid(t *testing.T) {
+	for _, root := range []string{"testdata/linux/v1", "testdata/linux/v2"} {
+		t.Run(root, func(t *testing.T) {
+			t.Setenv("HOST_SYS", root+"/sys")
+			t.Setenv("HOST_PROC", root+"/proc")
+
+			v, err := GetByPid(1234)
+			require.NoError(t, err)
+			assert.Equal(t, testPath, v.Path)
+			require.NotNil(t, v.CPU)
+			assert.InDelta(t, 37.5, v.CPU.Usage, 0.0001)
+			require.NotNil(t, v.Memory)
+			assert.Equal(t, uint64(12288000), v.Memory.Usage)
+			require.NotNil(t, v.IO)
+			require.NotNil(t, v.Pids)
+			assert.Equal(t, uint64(12), v.Pids.Current)
+		})
+	}
+}
+
+func TestPidCgroups(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux/v1/proc")
+	v, err := PidCgroups(1234)
+	require.NoError(t, err)
+	require.Len(t, v, 5)
+	assert.Equal(t, 4, v[3].HierarchyID)
+	assert.Equal(t, []string{"cpu", "cpuacct"}, v[3].Controllers)
+	assert.Equal(t, testPath, v[3].Path)
+	assert.Equal(t, []string{"name=systemd"}, v[4].Controllers)
+
+	t.Setenv("HOST_PROC", "testdata/linux/v2/proc")
+	v, err = PidCgroups(1234)
+	require.NoError(t, err)
+	require.Len(t, v, 1)
+	assert.Equal(t, 0, v[0].HierarchyID)
+	assert.Empty(t, v[0].Controllers)
+	assert.Equal(t, testPath, v[0].Path)
+}
+
+func TestInvalidPath(t *testing.T) {
+	_, err := Memory("/../../etc")
+	assert.Error(t, err)
+}
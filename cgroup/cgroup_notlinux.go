@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//go:build !linux
+
+package cgroup
+
+import "context"
+
+func IsUnifiedWithContext(_ context.Context) bool {
+	return false
+}
+
+func CPUWithContext(_ context.Context, _ string) (*CPUStat, error) {
+	return nil, ErrCgroupNotAvailable
+}
+
+func MemoryWithContext(_ context.Context, _ string) (*MemoryStat, error) {
+	return nil, ErrCgroupNotAvailable
+}
+
+func IOWithContext(_ context.Context, _ string) (*IOStat, error) {
+	return nil, ErrCgroupNotAvailable
+}
+
+func PidsWithContext(_ context.Context, _ string) (*PidsStat, error) {
+	return nil, ErrCgroupNotAvailable
+}
+
+func PressureWithContext(_ context.Context, _ string) ([]PressureStat, error) {
+	return nil, ErrCgroupNotAvailable
+}
+
+func GetWithContext(_ context.Context, _ string) (*Stat, error) {
+	return nil, ErrCgroupNotAvailable
+}
+
+func GetByPidWithContext(_ context.Context, _ int32) (*Stat, error) {
+	return nil, ErrCgroupNotAvailable
+}
+
+func PidCgroupsWithContext(_ context.Context, _ int32) ([]Membership, error) {
+	return nil, ErrCgroupNotAvailable
+}
+
+func ContainersWithContext(_ context.Context) ([]ContainerStat, error) {
+	return nil, ErrCgroupNotAvailable
+}
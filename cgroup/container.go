@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: BSD-3-Clause
+package cgroup
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Container runtimes recognised by Containers.
+const (
+	RuntimeDocker     = "docker"
+	RuntimeContainerd = "containerd"
+	RuntimeCRIO       = "cri-o"
+	RuntimePodman     = "podman"
+	// RuntimeUnknown is used for Kubernetes containers created with the
+	// cgroupfs driver, whose cgroup name does not tell the runtime.
+	RuntimeUnknown = ""
+)
+
+type ContainerStat struct {
+	ID      string  `json:"id"`
+	Runtime string  `json:"runtime"`
+	PodUID  string  `json:"podUID"`
+	Path    string  `json:"path"` // cgroup path, relative to the cgroup root
+	Pids    []int32 `json:"pids"`
+}
+
+func (c ContainerStat) String() string {
+	s, _ := json.Marshal(c)
+	return string(s)
+}
+
+// Containers returns the containers running on the host. Containers are
+// discovered from the cgroup hierarchy and /proc/<pid>/cgroup, so no
+// container runtime command or socket is needed.
+func Containers() ([]ContainerStat, error) {
+	return ContainersWithContext(context.Background())
+}
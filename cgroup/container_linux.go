@@ -0,0 +1,212 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//go:build linux
+
+package cgroup
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/internal/common"
+)
+
+// containerScopePatterns match the cgroup name of a container created
+// with the systemd cgroup driver.
+var containerScopePatterns = []struct {
+	re      *regexp.Regexp
+	runtime string
+}{
+	{regexp.MustCompile(`^docker-([0-9a-f]{64})\.scope$`), RuntimeDocker},
+	{regexp.MustCompile(`^cri-containerd-([0-9a-f]{64})\.scope$`), RuntimeContainerd},
+	{regexp.MustCompile(`^crio-([0-9a-f]{64})\.scope$`), RuntimeCRIO},
+	{regexp.MustCompile(`^libpod-([0-9a-f]{64})\.scope$`), RuntimePodman},
+}
+
+var (
+	containerIDPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	// podUIDPattern matches both "pod<uid>" (cgroupfs) and
+	// "kubepods-<qos>-pod<uid>.slice" (systemd, with "-" replaced by "_").
+	podUIDPattern = regexp.MustCompile(`pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})(?:\.slice)?$`)
+)
+
+// containerCgroupParents maps the parent cgroup name of a container created
+// with the cgroupfs driver to its runtime.
+var containerCgroupParents = map[string]string{
+	"docker":        RuntimeDocker,
+	"libpod_parent": RuntimePodman,
+}
+
+// parseContainerPath extracts the container of a cgroup path. The returned
+// ContainerStat has its Path truncated to the container cgroup, dropping
+// any nested cgroup such as "/init.scope".
+func parseContainerPath(path string) (ContainerStat, bool) {
+	elems := strings.Split(path, "/")
+	var podUID string
+	var inKubepods bool
+	for i, elem := range elems {
+		if strings.HasPrefix(elem, "kubepods") {
+			inKubepods = true
+		}
+		if m := podUIDPattern.FindStringSubmatch(elem); m != nil && inKubepods {
+			podUID = strings.ReplaceAll(m[1], "_", "-")
+			continue
+		}
+		for _, p := range containerScopePatterns {
+			if m := p.re.FindStringSubmatch(elem); m != nil {
+				return ContainerStat{
+					ID:      m[1],
+					Runtime: p.runtime,
+					PodUID:  podUID,
+					Path:    strings.Join(elems[:i+1], "/"),
+				}, true
+			}
+		}
+		if !containerIDPattern.MatchString(elem) || i == 0 {
+			continue
+		}
+		runtime, ok := containerCgroupParents[elems[i-1]]
+		if !ok && !inKubepods {
+			continue
+		}
+		if !ok {
+			runtime = RuntimeUnknown
+		}
+		return ContainerStat{
+			ID:      elem,
+			Runtime: runtime,
+			PodUID:  podUID,
+			Path:    strings.Join(elems[:i+1], "/"),
+		}, true
+	}
+	return ContainerStat{}, false
+}
+
+func ContainersWithContext(ctx context.Context) ([]ContainerStat, error) {
+	containers := make(map[string]*ContainerStat)
+
+	root := containerSearchRootWithContext(ctx)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// the cgroup may have been removed during the walk
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		rel := "/" + strings.TrimPrefix(filepath.ToSlash(strings.TrimPrefix(path, root)), "/")
+		c, ok := parseContainerPath(rel)
+		if !ok {
+			return nil
+		}
+		if _, exists := containers[c.ID]; !exists {
+			containers[c.ID] = &c
+		}
+		return filepath.SkipDir
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	pids, err := readPids(common.HostProcWithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	for _, pid := range pids {
+		cgroups, err := PidCgroupsWithContext(ctx, pid)
+		if err != nil {
+			// the process may have exited
+			continue
+		}
+		for _, m := range cgroups {
+			c, ok := parseContainerPath(m.Path)
+			if !ok {
+				continue
+			}
+			existing, exists := containers[c.ID]
+			if !exists {
+				existing = &c
+				containers[c.ID] = existing
+			}
+			existing.Pids = append(existing.Pids, pid)
+			break
+		}
+	}
+
+	ret := make([]ContainerStat, 0, len(containers))
+	for _, c := range containers {
+		if len(c.Pids) == 0 {
+			// processes are not visible from our /proc, e.g. another pid
+			// namespace, so fall back to cgroup.procs
+			c.Pids = readCgroupProcs(filepath.Join(root, c.Path))
+		}
+		sort.Slice(c.Pids, func(i, j int) bool { return c.Pids[i] < c.Pids[j] })
+		ret = append(ret, *c)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].ID < ret[j].ID })
+	return ret, nil
+}
+
+// containerSearchRootWithContext returns the hierarchy scanned for container
+// cgroups. On cgroup v1 the memory hierarchy is used as it is the one
+// every container runtime enables.
+func containerSearchRootWithContext(ctx context.Context) string {
+	if IsUnifiedWithContext(ctx) {
+		return common.HostSysWithContext(ctx, "fs/cgroup")
+	}
+	return common.HostSysWithContext(ctx, "fs/cgroup", "memory")
+}
+
+// readCgroupProcs returns the pids of a cgroup and all of its descendants.
+func readCgroupProcs(dir string) []int32 {
+	var ret []int32
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Name() != "cgroup.procs" {
+			return nil
+		}
+		lines, err := common.ReadLines(path)
+		if err != nil {
+			return nil
+		}
+		for _, line := range lines {
+			pid, err := strconv.ParseInt(strings.TrimSpace(line), 10, 32)
+			if err != nil {
+				continue
+			}
+			ret = append(ret, int32(pid))
+		}
+		return nil
+	})
+	return ret
+}
+
+func readPids(procPath string) ([]int32, error) {
+	d, err := os.Open(procPath)
+	if err != nil {
+		return nil, err
+	}
+	defer d.Close()
+
+	fnames, err := d.Readdirnames(-1)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]int32, 0, len(fnames))
+	for _, fname := range fnames {
+		pid, err := strconv.ParseInt(fname, 10, 32)
+		if err != nil {
+			// if not numeric name, just skip
+			continue
+		}
+		ret = append(ret, int32(pid))
+	}
+	return ret, nil
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//go:build linux
+
+package cgroup
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseContainerPath(t *testing.T) {
+	id := strings.Repeat("0123456789abcdef", 4)
+	cases := []struct {
+		path    string
+		ok      bool
+		runtime string
+		podUID  string
+		cpath   string
+	}{
+		{"/docker/" + id, true, RuntimeDocker, "", "/docker/" + id},
+		{"/system.slice/docker-" + id + ".scope", true, RuntimeDocker, "", "/system.slice/docker-" + id + ".scope"},
+		{"/system.slice/crio-" + id + ".scope", true, RuntimeCRIO, "", "/system.slice/crio-" + id + ".scope"},
+		{"/machine.slice/libpod-" + id + ".scope/container", true, RuntimePodman, "", "/machine.slice/libpod-" + id + ".scope"},
+		{"/libpod_parent/" + id, true, RuntimePodman, "", "/libpod_parent/" + id},
+		{
+			"/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod0f1e2d3c_4b5a_6978_8a9b_0c1d2e3f4a5b.slice/cri-containerd-" + id + ".scope",
+			true, RuntimeContainerd, "0f1e2d3c-4b5a-6978-8a9b-0c1d2e3f4a5b",
+			"/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod0f1e2d3c_4b5a_6978_8a9b_0c1d2e3f4a5b.slice/cri-containerd-" + id + ".scope",
+		},
+		{
+			"/kubepods/burstable/pod0f1e2d3c-4b5a-6978-8a9b-0c1d2e3f4a5b/" + id,
+			true, RuntimeUnknown, "0f1e2d3c-4b5a-6978-8a9b-0c1d2e3f4a5b",
+			"/kubepods/burstable/pod0f1e2d3c-4b5a-6978-8a9b-0c1d2e3f4a5b/" + id,
+		},
+		{"/system.slice/libpod-conmon-" + id + ".scope", false, "", "", ""},
+		{"/system.slice/sshd.service", false, "", "", ""},
+		{"/" + id, false, "", "", ""},
+		{"/", false, "", "", ""},
+	}
+	for _, tt := range cases {
+		t.Run(tt.path, func(t *testing.T) {
+			c, ok := parseContainerPath(tt.path)
+			require.Equal(t, tt.ok, ok)
+			if !ok {
+				return
+			}
+			assert.Equal(t, id, c.ID)
+			assert.Equal(t, tt.runtime, c.Runtime)
+			assert.Equal(t, tt.podUID, c.PodUID)
+			assert.Equal(t, tt.cpath, c.Path)
+		})
+	}
+}
+
+func TestContainers(t *testing.T) {
+	t.Setenv("HOST_SYS", "testdata/linux/containers/sys")
+	t.Setenv("HOST_PROC", "testdata/linux/containers/proc")
+
+	v, err := Containers()
+	require.NoError(t, err)
+	require.Len(t, v, 4)
+
+	assert.Equal(t, strings.Repeat("a", 64), v[0].ID)
+	assert.Equal(t, RuntimeDocker, v[0].Runtime)
+	assert.Equal(t, []int32{100, 101}, v[0].Pids)
+
+	assert.Equal(t, strings.Repeat("b", 64), v[1].ID)
+	assert.Equal(t, RuntimeContainerd, v[1].Runtime)
+	assert.Equal(t, "0f1e2d3c-4b5a-6978-8a9b-0c1d2e3f4a5b", v[1].PodUID)
+	assert.Equal(t, []int32{200}, v[1].Pids)
+
+	assert.Equal(t, strings.Repeat("c", 64), v[2].ID)
+	assert.Equal(t, RuntimePodman, v[2].Runtime)
+	assert.Equal(t, "/machine.slice/libpod-"+strings.Repeat("c", 64)+".scope", v[2].Path)
+	assert.Equal(t, []int32{300}, v[2].Pids)
+
+	// not visible in /proc, pids come from cgroup.procs
+	assert.Equal(t, strings.Repeat("d", 64), v[3].ID)
+	assert.Equal(t, RuntimeCRIO, v[3].Runtime)
+	assert.Equal(t, []int32{400, 401}, v[3].Pids)
+}
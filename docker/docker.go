@@ -16,11 +16,18 @@ var (
 
 var invoke common.Invoker = common.Invoke{}
 
-const nanoseconds = 1e9
+const (
+	nanoseconds  = 1e9
+	microseconds = 1e6
+)
 
 type CgroupCPUStat struct {
 	cpu.TimesStat
 	Usage float64
+	// The following fields are only available on cgroup v2.
+	NrPeriods     uint64  `json:"nrPeriods"`
+	NrThrottled   uint64  `json:"nrThrottled"`
+	ThrottledTime float64 `json:"throttledTime"` // seconds
 }
 
 type CgroupMemStat struct {
@@ -56,6 +63,19 @@ type CgroupMemStat struct {
 	MemMaxUsageInBytes      uint64 `json:"memMaxUsageInBytes"`
 	MemLimitInBytes         uint64 `json:"memoryLimitInBytes"`
 	MemFailCnt              uint64 `json:"memoryFailcnt"`
+	Shmem                   uint64 `json:"shmem"`
+	Dirty                   uint64 `json:"dirty"`
+	Writeback               uint64 `json:"writeback"`
+	// The following fields are only available on cgroup v2.
+	KernelStack         uint64 `json:"kernelStack"`
+	Slab                uint64 `json:"slab"`
+	Sock                uint64 `json:"sock"`
+	MemSwapUsageInBytes uint64 `json:"memSwapUsageInBytes"`
+	MemEventsLow        uint64 `json:"memoryEventsLow"`
+	MemEventsHigh       uint64 `json:"memoryEventsHigh"`
+	MemEventsMax        uint64 `json:"memoryEventsMax"`
+	MemEventsOOM        uint64 `json:"memoryEventsOom"`
+	MemEventsOOMKill    uint64 `json:"memoryEventsOomKill"`
 }
 
 func (m CgroupMemStat) String() string {
@@ -63,6 +83,27 @@ func (m CgroupMemStat) String() string {
 	return string(s)
 }
 
+type CgroupIODeviceStat struct {
+	Major        uint64 `json:"major"`
+	Minor        uint64 `json:"minor"`
+	ReadBytes    uint64 `json:"readBytes"`
+	WriteBytes   uint64 `json:"writeBytes"`
+	ReadIOs      uint64 `json:"readIOs"`
+	WriteIOs     uint64 `json:"writeIOs"`
+	DiscardBytes uint64 `json:"discardBytes"`
+	DiscardIOs   uint64 `json:"discardIOs"`
+}
+
+type CgroupIOStat struct {
+	ContainerID string               `json:"containerID"`
+	Devices     []CgroupIODeviceStat `json:"devices"`
+}
+
+func (i CgroupIOStat) String() string {
+	s, _ := json.Marshal(i)
+	return string(s)
+}
+
 type CgroupDockerStat struct {
 	ContainerID string `json:"containerID"`
 	Name        string `json:"name"`
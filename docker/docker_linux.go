@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
 	"path"
@@ -100,6 +101,9 @@ func CgroupCPUUsage(containerID, base string) (float64, error) {
 }
 
 func CgroupCPUWithContext(ctx context.Context, containerID, base string) (*CgroupCPUStat, error) {
+	if isCgroup2UnifiedModeWithContext(ctx) {
+		return cgroupCPUV2WithContext(ctx, containerID, base)
+	}
 	statfile, err := getCgroupFilePath(ctx, containerID, base, "cpuacct", "cpuacct.stat")
 	if err != nil {
 		return nil, err
@@ -139,6 +143,13 @@ func CgroupCPUWithContext(ctx context.Context, containerID, base string) (*Cgrou
 }
 
 func CgroupCPUUsageWithContext(ctx context.Context, containerID, base string) (float64, error) {
+	if isCgroup2UnifiedModeWithContext(ctx) {
+		stat, err := cgroupCPUV2WithContext(ctx, containerID, base)
+		if err != nil {
+			return 0.0, err
+		}
+		return stat.Usage, nil
+	}
 	usagefile, err := getCgroupFilePath(ctx, containerID, base, "cpuacct", "cpuacct.usage")
 	if err != nil {
 		return 0.0, err
@@ -165,11 +176,37 @@ func CgroupCPUUsageDocker(containerID string) (float64, error) {
 }
 
 func CgroupCPUDockerWithContext(ctx context.Context, containerID string) (*CgroupCPUStat, error) {
-	return CgroupCPUWithContext(ctx, containerID, common.HostSysWithContext(ctx, "fs/cgroup/cpuacct/docker"))
+	return CgroupCPUWithContext(ctx, containerID, getCgroupMountPath(ctx, "cpuacct", "docker"))
 }
 
 func CgroupCPUDockerUsageWithContext(ctx context.Context, containerID string) (float64, error) {
-	return CgroupCPUUsageWithContext(ctx, containerID, common.HostSysWithContext(ctx, "fs/cgroup/cpuacct/docker"))
+	return CgroupCPUUsageWithContext(ctx, containerID, getCgroupMountPath(ctx, "cpuacct", "docker"))
+}
+
+// cgroupCPUV2WithContext reads cpu.stat of the unified hierarchy.
+func cgroupCPUV2WithContext(ctx context.Context, containerID, base string) (*CgroupCPUStat, error) {
+	statfile, err := getCgroupFilePath(ctx, containerID, base, "cpuacct", "cpu.stat")
+	if err != nil {
+		return nil, err
+	}
+	stats, err := common.ReadCgroupFlatKeyed(statfile)
+	if err != nil {
+		return nil, err
+	}
+	// empty containerID means all cgroup
+	if containerID == "" {
+		containerID = "all"
+	}
+
+	ret := &CgroupCPUStat{}
+	ret.CPU = containerID
+	ret.User = float64(stats["user_usec"]) / microseconds
+	ret.System = float64(stats["system_usec"]) / microseconds
+	ret.Usage = float64(stats["usage_usec"]) / microseconds
+	ret.NrPeriods = stats["nr_periods"]
+	ret.NrThrottled = stats["nr_throttled"]
+	ret.ThrottledTime = float64(stats["throttled_usec"]) / microseconds
+	return ret, nil
 }
 
 func CgroupMem(containerID, base string) (*CgroupMemStat, error) {
@@ -198,14 +235,26 @@ func CgroupMemWithContext(ctx context.Context, containerID, base string) (*Cgrou
 			continue
 		}
 		switch fields[0] {
-		case "cache":
+		case "cache", "file":
 			ret.Cache = v
-		case "rss":
+		case "rss", "anon":
 			ret.RSS = v
-		case "rssHuge", "rss_huge":
+		case "rssHuge", "rss_huge", "anon_thp":
 			ret.RSSHuge = v
-		case "mappedFile", "mapped_file":
+		case "mappedFile", "mapped_file", "file_mapped":
 			ret.MappedFile = v
+		case "shmem":
+			ret.Shmem = v
+		case "dirty", "file_dirty":
+			ret.Dirty = v
+		case "writeback", "file_writeback":
+			ret.Writeback = v
+		case "kernel_stack":
+			ret.KernelStack = v
+		case "slab":
+			ret.Slab = v
+		case "sock":
+			ret.Sock = v
 		case "pgpgin":
 			ret.Pgpgin = v
 		case "pgpgout":
@@ -255,6 +304,11 @@ func CgroupMemWithContext(ctx context.Context, containerID, base string) (*Cgrou
 		}
 	}
 
+	if isCgroup2UnifiedModeWithContext(ctx) {
+		fillCgroupMemV2WithContext(ctx, ret, containerID, base)
+		return ret, nil
+	}
+
 	r, err := getCgroupMemFile(ctx, containerID, base, "memory.usage_in_bytes")
 	if err == nil {
 		ret.MemUsageInBytes = r
@@ -280,7 +334,116 @@ func CgroupMemDocker(containerID string) (*CgroupMemStat, error) {
 }
 
 func CgroupMemDockerWithContext(ctx context.Context, containerID string) (*CgroupMemStat, error) {
-	return CgroupMemWithContext(ctx, containerID, common.HostSysWithContext(ctx, "fs/cgroup/memory/docker"))
+	return CgroupMemWithContext(ctx, containerID, getCgroupMountPath(ctx, "memory", "docker"))
+}
+
+// fillCgroupMemV2WithContext fills the usage, limit and event counters which
+// live in separate files on the unified hierarchy.
+func fillCgroupMemV2WithContext(ctx context.Context, ret *CgroupMemStat, containerID, base string) {
+	r, err := getCgroupMemFile(ctx, containerID, base, "memory.current")
+	if err == nil {
+		ret.MemUsageInBytes = r
+	}
+	// memory.peak requires kernel >= 5.19
+	r, err = getCgroupMemFile(ctx, containerID, base, "memory.peak")
+	if err == nil {
+		ret.MemMaxUsageInBytes = r
+	}
+	r, err = getCgroupMemFile(ctx, containerID, base, "memory.max")
+	if err == nil {
+		ret.MemLimitInBytes = r
+		ret.HierarchicalMemoryLimit = r
+	}
+	r, err = getCgroupMemFile(ctx, containerID, base, "memory.swap.current")
+	if err == nil {
+		ret.MemSwapUsageInBytes = r
+	}
+
+	eventsfile, err := getCgroupFilePath(ctx, containerID, base, "memory", "memory.events")
+	if err != nil {
+		return
+	}
+	events, err := common.ReadCgroupFlatKeyed(eventsfile)
+	if err != nil {
+		return
+	}
+	ret.MemEventsLow = events["low"]
+	ret.MemEventsHigh = events["high"]
+	ret.MemEventsMax = events["max"]
+	ret.MemEventsOOM = events["oom"]
+	ret.MemEventsOOMKill = events["oom_kill"]
+	// v2 has no failcnt, the closest equivalent is the number of times
+	// the usage hit memory.max.
+	ret.MemFailCnt = events["max"]
+}
+
+// CgroupIO returns specified cgroup id block I/O statistics.
+// On cgroup v1, blkio.throttle.io_service_bytes and blkio.throttle.io_serviced
+// are used. On cgroup v2, io.stat is used.
+func CgroupIO(containerID, base string) (*CgroupIOStat, error) {
+	return CgroupIOWithContext(context.Background(), containerID, base)
+}
+
+func CgroupIOWithContext(ctx context.Context, containerID, base string) (*CgroupIOStat, error) {
+	var devices []common.CgroupIODevice
+	if isCgroup2UnifiedModeWithContext(ctx) {
+		statfile, err := getCgroupFilePath(ctx, containerID, base, "blkio", "io.stat")
+		if err != nil {
+			return nil, err
+		}
+		if devices, err = common.ReadCgroupIOStat(statfile); err != nil {
+			return nil, err
+		}
+	} else {
+		bytesfile, err := getCgroupFilePath(ctx, containerID, base, "blkio", "blkio.throttle.io_service_bytes")
+		if err != nil {
+			return nil, err
+		}
+		iosfile, err := getCgroupFilePath(ctx, containerID, base, "blkio", "blkio.throttle.io_serviced")
+		if err != nil {
+			return nil, err
+		}
+		if devices, err = common.ReadCgroupBlkio(bytesfile, iosfile); err != nil {
+			return nil, err
+		}
+	}
+
+	// empty containerID means all cgroup
+	if containerID == "" {
+		containerID = "all"
+	}
+	ret := &CgroupIOStat{
+		ContainerID: containerID,
+		Devices:     make([]CgroupIODeviceStat, 0, len(devices)),
+	}
+	for _, d := range devices {
+		ret.Devices = append(ret.Devices, CgroupIODeviceStat(d))
+	}
+	return ret, nil
+}
+
+func CgroupIODocker(containerID string) (*CgroupIOStat, error) {
+	return CgroupIODockerWithContext(context.Background(), containerID)
+}
+
+func CgroupIODockerWithContext(ctx context.Context, containerID string) (*CgroupIOStat, error) {
+	return CgroupIOWithContext(ctx, containerID, getCgroupMountPath(ctx, "blkio", "docker"))
+}
+
+// isCgroup2UnifiedModeWithContext returns true if the cgroup v2 unified
+// hierarchy is mounted at /sys/fs/cgroup.
+func isCgroup2UnifiedModeWithContext(ctx context.Context) bool {
+	return common.PathExists(common.HostSysWithContext(ctx, "fs/cgroup/cgroup.controllers"))
+}
+
+// getCgroupMountPath returns the path of elem under the hierarchy of the
+// target controller. On cgroup v2 all controllers share a single hierarchy,
+// so target is ignored.
+func getCgroupMountPath(ctx context.Context, target, elem string) string {
+	if isCgroup2UnifiedModeWithContext(ctx) {
+		return common.HostSysWithContext(ctx, "fs/cgroup", elem)
+	}
+	return common.HostSysWithContext(ctx, "fs/cgroup", target, elem)
 }
 
 // getCgroupFilePath constructs file path to get targeted stats file.
@@ -291,13 +454,13 @@ func getCgroupFilePath(ctx context.Context, containerID, base, target, file stri
 		return "", fmt.Errorf("invalid container ID %q", containerID)
 	}
 	if base == "" {
-		base = common.HostSysWithContext(ctx, fmt.Sprintf("fs/cgroup/%s/docker", target))
+		base = getCgroupMountPath(ctx, target, "docker")
 	}
 	statfile := path.Join(base, containerID, file)
 
 	if _, err := os.Stat(statfile); os.IsNotExist(err) {
 		statfile = path.Join(
-			common.HostSysWithContext(ctx, fmt.Sprintf("fs/cgroup/%s/system.slice", target)), "docker-"+containerID+".scope", file)
+			getCgroupMountPath(ctx, target, "system.slice"), "docker-"+containerID+".scope", file)
 	}
 
 	return statfile, nil
@@ -316,5 +479,9 @@ func getCgroupMemFile(ctx context.Context, containerID, base, file string) (uint
 	if len(lines) != 1 {
 		return 0, fmt.Errorf("wrong format file: %s", statfile)
 	}
+	// cgroup v2 limit files use "max" for unlimited
+	if lines[0] == "max" {
+		return math.MaxUint64, nil
+	}
 	return strconv.ParseUint(lines[0], 10, 64)
 }
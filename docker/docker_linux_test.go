@@ -5,10 +5,13 @@ package docker
 
 import (
 	"context"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/shirou/gopsutil/v4/cpu"
 )
 
 func TestGetDockerIDList(_ *testing.T) {
@@ -75,3 +78,102 @@ func TestCgroupMemInvalidId(t *testing.T) {
 	_, err := CgroupMemDocker("bad id")
 	assert.Errorf(t, err, "Expected path does not exist error")
 }
+
+const testContainerID = "6b5ba1b3a8fd83e1ac1e6c7d3eaf6a0e8d4c5b2f1a0e9d8c7b6a5f4e3d2c1b0a"
+
+func TestCgroupCPUV1(t *testing.T) {
+	t.Setenv("HOST_SYS", "testdata/linux/cgroupv1")
+
+	v, err := CgroupCPUDocker(testContainerID)
+	require.NoError(t, err)
+	assert.Equal(t, testContainerID, v.CPU)
+	assert.InDelta(t, 2500/cpu.ClocksPerSec, v.User, 0.0001)
+	assert.InDelta(t, 1200/cpu.ClocksPerSec, v.System, 0.0001)
+	assert.InDelta(t, 37.5, v.Usage, 0.0001)
+}
+
+func TestCgroupCPUV2(t *testing.T) {
+	t.Setenv("HOST_SYS", "testdata/linux/cgroupv2")
+
+	v, err := CgroupCPUDocker(testContainerID)
+	require.NoError(t, err)
+	assert.Equal(t, testContainerID, v.CPU)
+	assert.InDelta(t, 25.0, v.User, 0.0001)
+	assert.InDelta(t, 12.5, v.System, 0.0001)
+	assert.InDelta(t, 37.5, v.Usage, 0.0001)
+	assert.Equal(t, uint64(100), v.NrPeriods)
+	assert.Equal(t, uint64(7), v.NrThrottled)
+	assert.InDelta(t, 0.35, v.ThrottledTime, 0.0001)
+
+	usage, err := CgroupCPUUsageDocker(testContainerID)
+	require.NoError(t, err)
+	assert.InDelta(t, 37.5, usage, 0.0001)
+}
+
+func TestCgroupMemV1(t *testing.T) {
+	t.Setenv("HOST_SYS", "testdata/linux/cgroupv1")
+
+	v, err := CgroupMemDocker(testContainerID)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4096000), v.Cache)
+	assert.Equal(t, uint64(8192000), v.RSS)
+	assert.Equal(t, uint64(2097152), v.RSSHuge)
+	assert.Equal(t, uint64(1024000), v.MappedFile)
+	assert.Equal(t, uint64(8192), v.Shmem)
+	assert.Equal(t, uint64(4096), v.Dirty)
+	assert.Equal(t, uint64(12000), v.TotalPgFault)
+	assert.Equal(t, uint64(12288000), v.MemUsageInBytes)
+	assert.Equal(t, uint64(16384000), v.MemMaxUsageInBytes)
+	assert.Equal(t, uint64(536870912), v.MemLimitInBytes)
+	assert.Equal(t, uint64(3), v.MemFailCnt)
+}
+
+func TestCgroupMemV2(t *testing.T) {
+	t.Setenv("HOST_SYS", "testdata/linux/cgroupv2")
+
+	v, err := CgroupMemDocker(testContainerID)
+	require.NoError(t, err)
+	assert.Equal(t, testContainerID, v.ContainerID)
+	assert.Equal(t, uint64(4096000), v.Cache)
+	assert.Equal(t, uint64(8192000), v.RSS)
+	assert.Equal(t, uint64(2097152), v.RSSHuge)
+	assert.Equal(t, uint64(1024000), v.MappedFile)
+	assert.Equal(t, uint64(8192000), v.ActiveAnon)
+	assert.Equal(t, uint64(12000), v.Pgfault)
+	assert.Equal(t, uint64(65536), v.KernelStack)
+	assert.Equal(t, uint64(393216), v.Slab)
+	assert.Equal(t, uint64(4096), v.Sock)
+	assert.Equal(t, uint64(12288000), v.MemUsageInBytes)
+	assert.Equal(t, uint64(16384000), v.MemMaxUsageInBytes)
+	assert.Equal(t, uint64(math.MaxUint64), v.MemLimitInBytes)
+	assert.Equal(t, uint64(3), v.MemEventsMax)
+	assert.Equal(t, uint64(1), v.MemEventsOOM)
+	assert.Equal(t, uint64(1), v.MemEventsOOMKill)
+	assert.Equal(t, uint64(3), v.MemFailCnt)
+}
+
+func TestCgroupIO(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		hostSys string
+		devices int
+	}{
+		{"v1", "testdata/linux/cgroupv1", 1},
+		{"v2", "testdata/linux/cgroupv2", 2},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOST_SYS", tt.hostSys)
+
+			v, err := CgroupIODocker(testContainerID)
+			require.NoError(t, err)
+			require.Len(t, v.Devices, tt.devices)
+			d := v.Devices[0]
+			assert.Equal(t, uint64(8), d.Major)
+			assert.Equal(t, uint64(0), d.Minor)
+			assert.Equal(t, uint64(1048576), d.ReadBytes)
+			assert.Equal(t, uint64(2097152), d.WriteBytes)
+			assert.Equal(t, uint64(256), d.ReadIOs)
+			assert.Equal(t, uint64(512), d.WriteIOs)
+		})
+	}
+}
@@ -64,3 +64,20 @@ func CgroupMemDocker(containerID string) (*CgroupMemStat, error) {
 func CgroupMemDockerWithContext(ctx context.Context, containerID string) (*CgroupMemStat, error) {
 	return CgroupMemWithContext(ctx, containerID, common.HostSysWithContext(ctx, "fs/cgroup/memory/docker"))
 }
+
+// CgroupIO returns specified cgroup id block I/O statistics.
+func CgroupIO(containerID, base string) (*CgroupIOStat, error) {
+	return CgroupIOWithContext(context.Background(), containerID, base)
+}
+
+func CgroupIOWithContext(_ context.Context, _, _ string) (*CgroupIOStat, error) {
+	return nil, ErrCgroupNotAvailable
+}
+
+func CgroupIODocker(containerID string) (*CgroupIOStat, error) {
+	return CgroupIODockerWithContext(context.Background(), containerID)
+}
+
+func CgroupIODockerWithContext(ctx context.Context, containerID string) (*CgroupIOStat, error) {
+	return CgroupIOWithContext(ctx, containerID, common.HostSysWithContext(ctx, "fs/cgroup/blkio/docker"))
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//go:build linux
+
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CgroupIODevice is the block I/O of a device in a cgroup, shared by the
+// cgroup and docker packages which convert it to their own type.
+type CgroupIODevice struct {
+	Major        uint64
+	Minor        uint64
+	ReadBytes    uint64
+	WriteBytes   uint64
+	ReadIOs      uint64
+	WriteIOs     uint64
+	DiscardBytes uint64
+	DiscardIOs   uint64
+}
+
+// ReadCgroupIOStat parses the cgroup v2 io.stat file, whose lines look like
+// "8:0 rbytes=1 wbytes=2 rios=3 wios=4 dbytes=5 dios=6". The devices are
+// ordered by major and minor number.
+func ReadCgroupIOStat(filename string) ([]CgroupIODevice, error) {
+	lines, err := ReadLines(filename)
+	if err != nil {
+		return nil, err
+	}
+	devices := make(map[string]*CgroupIODevice)
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		d, err := getCgroupIODevice(devices, fields[0])
+		if err != nil {
+			continue
+		}
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			v, err := strconv.ParseUint(kv[1], 10, 64)
+			if err != nil {
+				continue
+			}
+			switch kv[0] {
+			case "rbytes":
+				d.ReadBytes = v
+			case "wbytes":
+				d.WriteBytes = v
+			case "rios":
+				d.ReadIOs = v
+			case "wios":
+				d.WriteIOs = v
+			case "dbytes":
+				d.DiscardBytes = v
+			case "dios":
+				d.DiscardIOs = v
+			}
+		}
+	}
+	return sortCgroupIODevices(devices), nil
+}
+
+// ReadCgroupBlkio parses the cgroup v1 blkio.throttle.io_service_bytes and
+// blkio.throttle.io_serviced files, whose lines look like "8:0 Read 1234".
+// The devices are ordered by major and minor number.
+func ReadCgroupBlkio(serviceBytesFile, servicedFile string) ([]CgroupIODevice, error) {
+	devices := make(map[string]*CgroupIODevice)
+	for _, file := range []string{serviceBytesFile, servicedFile} {
+		lines, err := ReadLines(file)
+		if err != nil {
+			return nil, err
+		}
+		isBytes := file == serviceBytesFile
+		for _, line := range lines {
+			fields := strings.Fields(line)
+			if len(fields) != 3 {
+				// skip the trailing "Total <n>" line
+				continue
+			}
+			d, err := getCgroupIODevice(devices, fields[0])
+			if err != nil {
+				continue
+			}
+			v, err := strconv.ParseUint(fields[2], 10, 64)
+			if err != nil {
+				continue
+			}
+			switch {
+			case fields[1] == "Read" && isBytes:
+				d.ReadBytes = v
+			case fields[1] == "Write" && isBytes:
+				d.WriteBytes = v
+			case fields[1] == "Discard" && isBytes:
+				d.DiscardBytes = v
+			case fields[1] == "Read":
+				d.ReadIOs = v
+			case fields[1] == "Write":
+				d.WriteIOs = v
+			case fields[1] == "Discard":
+				d.DiscardIOs = v
+			}
+		}
+	}
+	return sortCgroupIODevices(devices), nil
+}
+
+// getCgroupIODevice returns the entry for a "major:minor" device, creating it if needed.
+func getCgroupIODevice(devices map[string]*CgroupIODevice, majorMinor string) (*CgroupIODevice, error) {
+	if d, ok := devices[majorMinor]; ok {
+		return d, nil
+	}
+	mm := strings.SplitN(majorMinor, ":", 2)
+	if len(mm) != 2 {
+		return nil, fmt.Errorf("invalid device %q", majorMinor)
+	}
+	major, err := strconv.ParseUint(mm[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	minor, err := strconv.ParseUint(mm[1], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	d := &CgroupIODevice{Major: major, Minor: minor}
+	devices[majorMinor] = d
+	return d, nil
+}
+
+func sortCgroupIODevices(devices map[string]*CgroupIODevice) []CgroupIODevice {
+	ret := make([]CgroupIODevice, 0, len(devices))
+	for _, d := range devices {
+		ret = append(ret, *d)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Major != ret[j].Major {
+			return ret[i].Major < ret[j].Major
+		}
+		return ret[i].Minor < ret[j].Minor
+	})
+	return ret
+}
+
+// ReadCgroupFlatKeyed reads a cgroup file made of "key value" lines, such as
+// cpu.stat, memory.stat or memory.events.
+func ReadCgroupFlatKeyed(filename string) (map[string]uint64, error) {
+	lines, err := ReadLines(filename)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]uint64, len(lines))
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		ret[fields[0]] = v
+	}
+	return ret, nil
+}
@@ -6,6 +6,7 @@ package common
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -341,3 +342,13 @@ func trimQuotes(s string) string {
 	}
 	return s
 }
+
+// ParseNsLink returns the inode of a namespace link of /proc/<pid>/ns, such
+// as "net:[4026531840]".
+func ParseNsLink(link string) (uint64, error) {
+	i := strings.IndexByte(link, '[')
+	if i == -1 || !strings.HasSuffix(link, "]") {
+		return 0, fmt.Errorf("invalid namespace link, %s", link)
+	}
+	return strconv.ParseUint(link[i+1:len(link)-1], 10, 64)
+}
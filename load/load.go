@@ -2,7 +2,11 @@
 package load
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/shirou/gopsutil/v4/internal/common"
 )
@@ -32,3 +36,88 @@ func (m MiscStat) String() string {
 	s, _ := json.Marshal(m)
 	return string(s)
 }
+
+// PressureAvg is one line of a Pressure Stall Information file.
+// Avg10, Avg60 and Avg300 are the percentage of time some (or all) tasks
+// were stalled over the last 10, 60 and 300 seconds. Total is the
+// accumulated stall time in microseconds.
+type PressureAvg struct {
+	Avg10  float64 `json:"avg10"`
+	Avg60  float64 `json:"avg60"`
+	Avg300 float64 `json:"avg300"`
+	Total  uint64  `json:"total"`
+}
+
+// PressureStat is the Pressure Stall Information of a resource such as
+// "cpu", "memory", "io" or "irq". Full is zero for resources which only
+// report "some", such as cpu before Linux 5.13.
+type PressureStat struct {
+	Resource string      `json:"resource"`
+	Some     PressureAvg `json:"some"`
+	Full     PressureAvg `json:"full"`
+}
+
+func (p PressureStat) String() string {
+	s, _ := json.Marshal(p)
+	return string(s)
+}
+
+// Pressure returns the system wide Pressure Stall Information of
+// cpu, memory, io and, when available, irq.
+func Pressure() ([]PressureStat, error) {
+	return PressureWithContext(context.Background())
+}
+
+// PressureByFile parses a Pressure Stall Information file such as
+// /proc/pressure/memory or a cgroup v2 memory.pressure file.
+func PressureByFile(resource, filename string) (*PressureStat, error) {
+	return PressureByFileWithContext(context.Background(), resource, filename)
+}
+
+// PressureByFileWithContext parses a Pressure Stall Information file:
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func PressureByFileWithContext(_ context.Context, resource, filename string) (*PressureStat, error) {
+	lines, err := common.ReadLines(filename)
+	if err != nil {
+		return nil, err
+	}
+	ret := &PressureStat{Resource: resource}
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		var avg *PressureAvg
+		switch fields[0] {
+		case "some":
+			avg = &ret.Some
+		case "full":
+			avg = &ret.Full
+		default:
+			continue
+		}
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("malformed %s: invalid field %q", filename, field)
+			}
+			var err error
+			switch kv[0] {
+			case "avg10":
+				avg.Avg10, err = strconv.ParseFloat(kv[1], 64)
+			case "avg60":
+				avg.Avg60, err = strconv.ParseFloat(kv[1], 64)
+			case "avg300":
+				avg.Avg300, err = strconv.ParseFloat(kv[1], 64)
+			case "total":
+				avg.Total, err = strconv.ParseUint(kv[1], 10, 64)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ret, nil
+}
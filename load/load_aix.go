@@ -5,6 +5,8 @@ package load
 
 import (
 	"context"
+
+	"github.com/shirou/gopsutil/v4/internal/common"
 )
 
 func Avg() (*AvgStat, error) {
@@ -17,3 +19,7 @@ func Avg() (*AvgStat, error) {
 func Misc() (*MiscStat, error) {
 	return MiscWithContext(context.Background())
 }
+
+func PressureWithContext(_ context.Context) ([]PressureStat, error) {
+	return nil, common.ErrNotImplementedError
+}
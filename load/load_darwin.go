@@ -9,6 +9,8 @@ import (
 	"unsafe"
 
 	"golang.org/x/sys/unix"
+
+	"github.com/shirou/gopsutil/v4/internal/common"
 )
 
 func Avg() (*AvgStat, error) {
@@ -65,3 +67,7 @@ func MiscWithContext(ctx context.Context) (*MiscStat, error) {
 
 	return &ret, nil
 }
+
+func PressureWithContext(_ context.Context) ([]PressureStat, error) {
+	return nil, common.ErrNotImplementedError
+}
@@ -24,3 +24,7 @@ func Misc() (*MiscStat, error) {
 func MiscWithContext(_ context.Context) (*MiscStat, error) {
 	return nil, common.ErrNotImplementedError
 }
+
+func PressureWithContext(_ context.Context) ([]PressureStat, error) {
+	return nil, common.ErrNotImplementedError
+}
@@ -116,6 +116,24 @@ func MiscWithContext(ctx context.Context) (*MiscStat, error) {
 	return ret, nil
 }
 
+var pressureResources = []string{"cpu", "memory", "io", "irq"}
+
+func PressureWithContext(ctx context.Context) ([]PressureStat, error) {
+	ret := make([]PressureStat, 0, len(pressureResources))
+	for _, resource := range pressureResources {
+		stat, err := PressureByFileWithContext(ctx, resource, common.HostProcWithContext(ctx, "pressure", resource))
+		if err != nil {
+			// irq requires Linux >= 6.1 with CONFIG_IRQ_TIME_ACCOUNTING
+			if resource == "irq" && os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		ret = append(ret, *stat)
+	}
+	return ret, nil
+}
+
 func readLoadAvgFromFile(ctx context.Context) ([]string, error) {
 	loadavgFilename := common.HostProcWithContext(ctx, "loadavg")
 	line, err := os.ReadFile(loadavgFilename)
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//go:build linux
+
+package load
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPressure(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux/proc")
+
+	v, err := Pressure()
+	require.NoError(t, err)
+	// irq is not in the testdata and must be skipped
+	require.Len(t, v, 3)
+
+	assert.Equal(t, "cpu", v[0].Resource)
+	assert.InDelta(t, 0.12, v[0].Some.Avg10, 0.0001)
+	assert.InDelta(t, 0.34, v[0].Some.Avg60, 0.0001)
+	assert.InDelta(t, 0.56, v[0].Some.Avg300, 0.0001)
+	assert.Equal(t, uint64(7890), v[0].Some.Total)
+
+	assert.Equal(t, "memory", v[1].Resource)
+	assert.InDelta(t, 1.0, v[1].Full.Avg10, 0.0001)
+	assert.Equal(t, uint64(765432), v[1].Full.Total)
+
+	assert.Equal(t, "io", v[2].Resource)
+	assert.Equal(t, uint64(99999999), v[2].Some.Total)
+	assert.Equal(t, uint64(88888888), v[2].Full.Total)
+}
+
+func TestPressureByFile(t *testing.T) {
+	v, err := PressureByFile("memory", "testdata/linux/proc/pressure/memory")
+	require.NoError(t, err)
+	assert.Equal(t, "memory", v.Resource)
+	assert.InDelta(t, 2.5, v.Some.Avg10, 0.0001)
+	assert.Equal(t, uint64(1234567), v.Some.Total)
+
+	_, err = PressureByFile("memory", "testdata/linux/proc/pressure/not_exist")
+	assert.Error(t, err)
+}
@@ -9,6 +9,8 @@ import (
 	"context"
 	"strconv"
 	"strings"
+
+	"github.com/shirou/gopsutil/v4/internal/common"
 )
 
 func Avg() (*AvgStat, error) {
@@ -73,3 +75,7 @@ func MiscWithContext(ctx context.Context) (*MiscStat, error) {
 
 	return &ret, nil
 }
+
+func PressureWithContext(_ context.Context) ([]PressureStat, error) {
+	return nil, common.ErrNotImplementedError
+}
@@ -91,3 +91,7 @@ func MiscWithContext(_ context.Context) (*MiscStat, error) {
 
 	return &ret, common.ErrNotImplementedError
 }
+
+func PressureWithContext(_ context.Context) ([]PressureStat, error) {
+	return nil, common.ErrNotImplementedError
+}
@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//go:build linux
+
+package net
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/shirou/gopsutil/v4/internal/common"
+)
+
+// ExConnectionStat is a ConnectionStat with Linux specific socket details.
+type ExConnectionStat struct {
+	ConnectionStat
+	// TxQueue and RxQueue are the tx_queue and rx_queue columns of
+	// /proc/net/*. For TCP they are the unacknowledged and unread bytes,
+	// for listening sockets RxQueue is the current accept backlog.
+	TxQueue uint32 `json:"txQueue"`
+	RxQueue uint32 `json:"rxQueue"`
+	// TCPInfo is only set for TCP sockets when netlink is available.
+	TCPInfo *ExTCPInfo `json:"tcpInfo,omitempty"`
+}
+
+func (c ExConnectionStat) String() string {
+	s, _ := json.Marshal(c)
+	return string(s)
+}
+
+// ExTCPInfo is a subset of struct tcp_info, see linux/tcp.h. Times are in
+// microseconds except LastDataSent, LastDataRecv and LastAckRecv which are
+// in milliseconds. Rates are in bytes per second.
+type ExTCPInfo struct {
+	State         uint8  `json:"state"`
+	CAState       uint8  `json:"caState"`
+	Retransmits   uint8  `json:"retransmits"`
+	RTO           uint32 `json:"rto"`
+	SndMSS        uint32 `json:"sndMss"`
+	RcvMSS        uint32 `json:"rcvMss"`
+	Unacked       uint32 `json:"unacked"`
+	Sacked        uint32 `json:"sacked"`
+	Lost          uint32 `json:"lost"`
+	Retrans       uint32 `json:"retrans"`
+	LastDataSent  uint32 `json:"lastDataSent"`
+	LastDataRecv  uint32 `json:"lastDataRecv"`
+	LastAckRecv   uint32 `json:"lastAckRecv"`
+	PMTU          uint32 `json:"pmtu"`
+	RTT           uint32 `json:"rtt"`
+	RTTVar        uint32 `json:"rttVar"`
+	MinRTT        uint32 `json:"minRtt"`
+	SndSsthresh   uint32 `json:"sndSsthresh"`
+	SndCwnd       uint32 `json:"sndCwnd"`
+	TotalRetrans  uint32 `json:"totalRetrans"`
+	PacingRate    uint64 `json:"pacingRate"`
+	DeliveryRate  uint64 `json:"deliveryRate"`
+	BytesSent     uint64 `json:"bytesSent"`
+	BytesAcked    uint64 `json:"bytesAcked"`
+	BytesReceived uint64 `json:"bytesReceived"`
+	BytesRetrans  uint64 `json:"bytesRetrans"`
+	SegsOut       uint32 `json:"segsOut"`
+	SegsIn        uint32 `json:"segsIn"`
+	NotsentBytes  uint32 `json:"notsentBytes"`
+}
+
+func (t ExTCPInfo) String() string {
+	s, _ := json.Marshal(t)
+	return string(s)
+}
+
+// ExNetNamespace is a network namespace and the processes in it. Any of
+// Pids can be used as NetNsPidKey to read the statistics of the namespace.
+type ExNetNamespace struct {
+	Inode uint64  `json:"inode"`
+	Pids  []int32 `json:"pids"`
+}
+
+func (n ExNetNamespace) String() string {
+	s, _ := json.Marshal(n)
+	return string(s)
+}
+
+type ExLinux struct{}
+
+func NewExLinux() *ExLinux {
+	return &ExLinux{}
+}
+
+// Connections returns a list of network connections opened with their
+// queue sizes. kind is the same as for Connections.
+func (ex *ExLinux) Connections(kind string) ([]ExConnectionStat, error) {
+	return ex.ConnectionsWithContext(context.Background(), kind)
+}
+
+func (*ExLinux) ConnectionsWithContext(ctx context.Context, kind string) ([]ExConnectionStat, error) {
+	return exConnectionsPidMaxWithContext(ctx, kind, 0, 0, false, false)
+}
+
+// ConnectionsPid returns a list of network connections opened by a process
+// with their queue sizes.
+func (ex *ExLinux) ConnectionsPid(kind string, pid int32) ([]ExConnectionStat, error) {
+	return ex.ConnectionsPidWithContext(context.Background(), kind, pid)
+}
+
+func (*ExLinux) ConnectionsPidWithContext(ctx context.Context, kind string, pid int32) ([]ExConnectionStat, error) {
+	return exConnectionsPidMaxWithContext(ctx, kind, pid, 0, false, false)
+}
+
+// ConnectionsWithTCPInfo is like Connections, and also fetches the tcp_info
+// of TCP sockets with NETLINK_SOCK_DIAG. TCPInfo is left nil when netlink
+// is not available.
+func (ex *ExLinux) ConnectionsWithTCPInfo(kind string) ([]ExConnectionStat, error) {
+	return ex.ConnectionsWithTCPInfoWithContext(context.Background(), kind)
+}
+
+func (*ExLinux) ConnectionsWithTCPInfoWithContext(ctx context.Context, kind string) ([]ExConnectionStat, error) {
+	return exConnectionsPidMaxWithContext(ctx, kind, 0, 0, false, true)
+}
+
+// ConnectionsPidWithTCPInfo is like ConnectionsPid, and also fetches the
+// tcp_info of TCP sockets.
+func (ex *ExLinux) ConnectionsPidWithTCPInfo(kind string, pid int32) ([]ExConnectionStat, error) {
+	return ex.ConnectionsPidWithTCPInfoWithContext(context.Background(), kind, pid)
+}
+
+func (*ExLinux) ConnectionsPidWithTCPInfoWithContext(ctx context.Context, kind string, pid int32) ([]ExConnectionStat, error) {
+	return exConnectionsPidMaxWithContext(ctx, kind, pid, 0, false, true)
+}
+
+// NetNamespaces returns the network namespaces found from /proc/*/ns/net,
+// ordered by inode. Namespaces without any process, such as the ones only
+// kept by a bind mount, are not found.
+func (ex *ExLinux) NetNamespaces() ([]ExNetNamespace, error) {
+	return ex.NetNamespacesWithContext(context.Background())
+}
+
+func (*ExLinux) NetNamespacesWithContext(ctx context.Context) ([]ExNetNamespace, error) {
+	pids, err := PidsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+
+	namespaces := make(map[uint64]*ExNetNamespace)
+	for _, pid := range pids {
+		link, err := os.Readlink(common.HostProcWithContext(ctx, strconv.Itoa(int(pid)), "ns", "net"))
+		if err != nil {
+			// the process may have exited, or we lack the permission
+			continue
+		}
+		inode, err := common.ParseNsLink(link)
+		if err != nil {
+			continue
+		}
+		ns, ok := namespaces[inode]
+		if !ok {
+			ns = &ExNetNamespace{Inode: inode}
+			namespaces[inode] = ns
+		}
+		ns.Pids = append(ns.Pids, pid)
+	}
+
+	ret := make([]ExNetNamespace, 0, len(namespaces))
+	for _, ns := range namespaces {
+		ret = append(ret, *ns)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Inode < ret[j].Inode })
+	return ret, nil
+}
@@ -11,6 +11,17 @@ import (
 
 var invoke common.Invoker = common.Invoke{}
 
+type NetNsKeyType string
+
+// NetNsPidKey is a context key to read the statistics of the network
+// namespace of a process instead of the one of the caller. It is honored by
+// IOCounters, ProtoCounters, ConntrackStats and Connections on Linux only.
+// Example of use:
+//
+//	ctx := context.WithValue(context.Background(), net.NetNsPidKey, int32(1234))
+//	counters, err := net.IOCountersWithContext(ctx, true)
+var NetNsPidKey = NetNsKeyType("netnsPid")
+
 type IOCountersStat struct {
 	Name        string `json:"name"`        // interface name
 	BytesSent   uint64 `json:"bytesSent"`   // number of bytes sent
@@ -291,18 +302,28 @@ func IOCountersByFile(pernic bool, filename string) ([]IOCountersStat, error) {
 // just the protocols in the list are returned.
 //
 // Available protocols:
-// [ip,icmp,icmpmsg,tcp,udp,udplite]
+// [ip,icmp,icmpmsg,tcp,udp,udplite,tcpext,ipext,ip6,icmp6,udp6,udplite6]
+//
+// On Linux, an empty protocols list also returns tcpext, ipext and the
+// IPv6 protocols, which older versions did not return. Callers which only
+// want the MIB-II protocols have to list them.
 //
 // Key naming contract:
-// The keys of the returned Stats map use MIB-II (RFC 1213) identifier
-// names — e.g. "InSegs", "OutSegs", "RetransSegs", "ActiveOpens",
-// "InDatagrams", "NoPorts". This contract was established by the
-// original Linux implementation, which sources its keys from
+// The keys of ip, icmp, icmpmsg, tcp, udp and udplite use MIB-II
+// (RFC 1213) identifier names — e.g. "InSegs", "OutSegs", "RetransSegs",
+// "ActiveOpens", "InDatagrams", "NoPorts". This contract was established
+// by the original Linux implementation, which sources its keys from
 // /proc/net/snmp (whose headers are MIB-II names). Per-platform
 // implementations are expected to map their native counter sources
 // (e.g. AIX `netstat -s`, BSD sysctl) to these MIB-II names.
-// Native counters that have no MIB-II equivalent are not exposed
-// through this API.
+//
+// The other protocols are Linux only, and their keys are the native names
+// of the kernel:
+//   - tcpext and ipext are the TcpExt and IpExt lines of /proc/net/netstat,
+//     with keys such as "ListenDrops", "SyncookiesSent" or "InOctets".
+//   - ip6, icmp6, udp6 and udplite6 are read from /proc/net/snmp6, with
+//     the protocol prefix removed from the keys, e.g. "Udp6InDatagrams" is
+//     returned as "InDatagrams".
 //
 // Not Implemented for FreeBSD, Windows, OpenBSD, Darwin, Solaris.
 func ProtoCounters(protocols []string) ([]ProtoCountersStat, error) {
@@ -322,6 +343,14 @@ func ConntrackStats(percpu bool) ([]ConntrackStat, error) {
 }
 
 // Return a list of network connections opened.
+//
+// On Linux the sockets are fetched with NETLINK_SOCK_DIAG when available,
+// which is much faster than parsing /proc/net/* on hosts with many
+// sockets. /proc/net/* is still used when netlink is not permitted or
+// HOST_PROC is set.
+//
+// On Linux kind may also be raw, raw4, raw6, udplite, udplite4, udplite6,
+// sctp, packet or netlink. These are not included in "all".
 func Connections(kind string) ([]ConnectionStat, error) {
 	return ConnectionsWithContext(context.Background(), kind)
 }
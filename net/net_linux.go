@@ -40,7 +40,7 @@ const ( // Conntrack Column numbers
 )
 
 func IOCountersWithContext(ctx context.Context, pernic bool) ([]IOCountersStat, error) {
-	filename := common.HostProcWithContext(ctx, "net/dev")
+	filename := hostProcNetWithContext(ctx, "dev")
 	return IOCountersByFileWithContext(ctx, pernic, filename)
 }
 
@@ -147,6 +147,23 @@ var netProtocols = []string{
 	"tcp",
 	"udp",
 	"udplite",
+	"tcpext",
+	"ipext",
+	"ip6",
+	"icmp6",
+	"udp6",
+	"udplite6",
+}
+
+// snmp6Prefixes maps the key prefixes of /proc/net/snmp6 to their protocol.
+var snmp6Prefixes = []struct {
+	prefix   string
+	protocol string
+}{
+	{"Ip6", "ip6"},
+	{"Icmp6", "icmp6"},
+	{"UdpLite6", "udplite6"},
+	{"Udp6", "udp6"},
 }
 
 func ProtoCountersWithContext(ctx context.Context, protocols []string) ([]ProtoCountersStat, error) {
@@ -160,12 +177,41 @@ func ProtoCountersWithContext(ctx context.Context, protocols []string) ([]ProtoC
 		protos[p] = true
 	}
 
-	filename := common.HostProcWithContext(ctx, "net/snmp")
+	s, err := protoCountersFromHeaderFile(hostProcNetWithContext(ctx, "snmp"), protos)
+	if err != nil {
+		return nil, err
+	}
+	stats = append(stats, s...)
+
+	if protos["tcpext"] || protos["ipext"] {
+		s, err := protoCountersFromHeaderFile(hostProcNetWithContext(ctx, "netstat"), protos)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		stats = append(stats, s...)
+	}
+
+	if protos["ip6"] || protos["icmp6"] || protos["udp6"] || protos["udplite6"] {
+		// snmp6 does not exist when IPv6 is disabled
+		s, err := protoCountersFromSnmp6File(hostProcNetWithContext(ctx, "snmp6"), protos)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		stats = append(stats, s...)
+	}
+	return stats, nil
+}
+
+// protoCountersFromHeaderFile parses files such as /proc/net/snmp and
+// /proc/net/netstat, where each protocol has a header line with the counter
+// names followed by a line with their values.
+func protoCountersFromHeaderFile(filename string, protos map[string]bool) ([]ProtoCountersStat, error) {
 	lines, err := common.ReadLines(filename)
 	if err != nil {
 		return nil, err
 	}
 
+	var stats []ProtoCountersStat
 	linecount := len(lines)
 	for i := 0; i < linecount; i++ {
 		line := lines[i]
@@ -185,6 +231,9 @@ func ProtoCountersWithContext(ctx context.Context, protocols []string) ([]ProtoC
 
 		// Read data line
 		i++
+		if i >= linecount {
+			return nil, errors.New(filename + " is not formatted correctly, expected data line.")
+		}
 		statValues := strings.Split(lines[i][r+2:], " ")
 		if len(statNames) != len(statValues) {
 			return nil, errors.New(filename + " is not formatted correctly, expected same number of columns.")
@@ -205,6 +254,64 @@ func ProtoCountersWithContext(ctx context.Context, protocols []string) ([]ProtoC
 	return stats, nil
 }
 
+// protoCountersFromSnmp6File parses /proc/net/snmp6, which has one
+// "<Proto><Name> <value>" pair per line. The protocol prefix is removed
+// from the returned keys so they match the names used for IPv4, e.g.
+// "Udp6InDatagrams" is returned as "InDatagrams" of "udp6".
+func protoCountersFromSnmp6File(filename string, protos map[string]bool) ([]ProtoCountersStat, error) {
+	lines, err := common.ReadLines(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []ProtoCountersStat
+	index := make(map[string]int)
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		for _, p := range snmp6Prefixes {
+			if !strings.HasPrefix(fields[0], p.prefix) {
+				continue
+			}
+			if !protos[p.protocol] {
+				break
+			}
+			value, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			i, ok := index[p.protocol]
+			if !ok {
+				i = len(stats)
+				index[p.protocol] = i
+				stats = append(stats, ProtoCountersStat{
+					Protocol: p.protocol,
+					Stats:    make(map[string]int64),
+				})
+			}
+			stats[i].Stats[strings.TrimPrefix(fields[0], p.prefix)] = value
+			break
+		}
+	}
+	return stats, nil
+}
+
+// hostProcNetWithContext returns the path of a file of /proc/net, or of
+// /proc/<pid>/net if ctx has a NetNsPidKey.
+func hostProcNetWithContext(ctx context.Context, elem ...string) string {
+	if pid := netNsPidWithContext(ctx); pid > 0 {
+		return common.HostProcWithContext(ctx, append([]string{strconv.Itoa(int(pid)), "net"}, elem...)...)
+	}
+	return common.HostProcWithContext(ctx, append([]string{"net"}, elem...)...)
+}
+
+func netNsPidWithContext(ctx context.Context) int32 {
+	pid, _ := ctx.Value(NetNsPidKey).(int32)
+	return pid
+}
+
 func FilterCountersWithContext(ctx context.Context) ([]FilterStat, error) {
 	countfile := common.HostProcWithContext(ctx, "sys/net/netfilter/nf_conntrack_count")
 	maxfile := common.HostProcWithContext(ctx, "sys/net/netfilter/nf_conntrack_max")
@@ -231,7 +338,7 @@ func FilterCountersWithContext(ctx context.Context) ([]FilterStat, error) {
 
 // ConntrackStatsWithContext returns more detailed info about the conntrack table
 func ConntrackStatsWithContext(ctx context.Context, percpu bool) ([]ConntrackStat, error) {
-	return conntrackStatsFromFile(common.HostProcWithContext(ctx, "net/stat/nf_conntrack"), percpu)
+	return conntrackStatsFromFile(hostProcNetWithContext(ctx, "stat/nf_conntrack"), percpu)
 }
 
 // conntrackStatsFromFile returns more detailed info about the conntrack table
@@ -294,50 +401,113 @@ var tcpStatuses = map[string]string{
 type netConnectionKindType struct {
 	family   uint32
 	sockType uint32
+	// protocol is the IPPROTO of inet kinds which can be fetched with
+	// NETLINK_SOCK_DIAG, 0 otherwise.
+	protocol uint8
 	filename string
 }
 
 var kindTCP4 = netConnectionKindType{
 	family:   syscall.AF_INET,
 	sockType: syscall.SOCK_STREAM,
+	protocol: syscall.IPPROTO_TCP,
 	filename: "tcp",
 }
 
 var kindTCP6 = netConnectionKindType{
 	family:   syscall.AF_INET6,
 	sockType: syscall.SOCK_STREAM,
+	protocol: syscall.IPPROTO_TCP,
 	filename: "tcp6",
 }
 
 var kindUDP4 = netConnectionKindType{
 	family:   syscall.AF_INET,
 	sockType: syscall.SOCK_DGRAM,
+	protocol: syscall.IPPROTO_UDP,
 	filename: "udp",
 }
 
 var kindUDP6 = netConnectionKindType{
 	family:   syscall.AF_INET6,
 	sockType: syscall.SOCK_DGRAM,
+	protocol: syscall.IPPROTO_UDP,
 	filename: "udp6",
 }
 
+var kindUDPLite4 = netConnectionKindType{
+	family:   syscall.AF_INET,
+	sockType: syscall.SOCK_DGRAM,
+	protocol: syscall.IPPROTO_UDPLITE,
+	filename: "udplite",
+}
+
+var kindUDPLite6 = netConnectionKindType{
+	family:   syscall.AF_INET6,
+	sockType: syscall.SOCK_DGRAM,
+	protocol: syscall.IPPROTO_UDPLITE,
+	filename: "udplite6",
+}
+
+var kindRAW4 = netConnectionKindType{
+	family:   syscall.AF_INET,
+	sockType: syscall.SOCK_RAW,
+	filename: "raw",
+}
+
+var kindRAW6 = netConnectionKindType{
+	family:   syscall.AF_INET6,
+	sockType: syscall.SOCK_RAW,
+	filename: "raw6",
+}
+
+// SCTP endpoints and associations of both IPv4 and IPv6 are listed in the
+// same files, the family and type are read from each line.
+var kindSCTPEndpoints = netConnectionKindType{
+	filename: "sctp/eps",
+}
+
+var kindSCTPAssocs = netConnectionKindType{
+	filename: "sctp/assocs",
+}
+
+var kindPacket = netConnectionKindType{
+	family:   syscall.AF_PACKET,
+	filename: "packet",
+}
+
+var kindNetlink = netConnectionKindType{
+	family:   syscall.AF_NETLINK,
+	sockType: syscall.SOCK_RAW,
+	filename: "netlink",
+}
+
 var kindUNIX = netConnectionKindType{
 	family:   syscall.AF_UNIX,
 	filename: "unix",
 }
 
 var netConnectionKindMap = map[string][]netConnectionKindType{
-	"all":   {kindTCP4, kindTCP6, kindUDP4, kindUDP6, kindUNIX},
-	"tcp":   {kindTCP4, kindTCP6},
-	"tcp4":  {kindTCP4},
-	"tcp6":  {kindTCP6},
-	"udp":   {kindUDP4, kindUDP6},
-	"udp4":  {kindUDP4},
-	"udp6":  {kindUDP6},
-	"unix":  {kindUNIX},
-	"inet":  {kindTCP4, kindTCP6, kindUDP4, kindUDP6},
-	"inet4": {kindTCP4, kindUDP4},
-	"inet6": {kindTCP6, kindUDP6},
+	"all":      {kindTCP4, kindTCP6, kindUDP4, kindUDP6, kindUNIX},
+	"tcp":      {kindTCP4, kindTCP6},
+	"tcp4":     {kindTCP4},
+	"tcp6":     {kindTCP6},
+	"udp":      {kindUDP4, kindUDP6},
+	"udp4":     {kindUDP4},
+	"udp6":     {kindUDP6},
+	"unix":     {kindUNIX},
+	"inet":     {kindTCP4, kindTCP6, kindUDP4, kindUDP6},
+	"inet4":    {kindTCP4, kindUDP4},
+	"inet6":    {kindTCP6, kindUDP6},
+	"udplite":  {kindUDPLite4, kindUDPLite6},
+	"udplite4": {kindUDPLite4},
+	"udplite6": {kindUDPLite6},
+	"raw":      {kindRAW4, kindRAW6},
+	"raw4":     {kindRAW4},
+	"raw6":     {kindRAW6},
+	"sctp":     {kindSCTPEndpoints, kindSCTPAssocs},
+	"packet":   {kindPacket},
+	"netlink":  {kindNetlink},
 }
 
 type inodeMap struct {
@@ -356,6 +526,9 @@ type connTmp struct {
 	boundPid int32
 	path     string
 	inode    string
+	txQueue  uint32
+	rxQueue  uint32
+	tcpInfo  *ExTCPInfo
 }
 
 func ConnectionsWithContext(ctx context.Context, kind string) ([]ConnectionStat, error) {
@@ -391,6 +564,18 @@ func ConnectionsPidMaxWithoutUidsWithContext(ctx context.Context, kind string, p
 }
 
 func connectionsPidMaxWithoutUidsWithContext(ctx context.Context, kind string, pid int32, maxConn int, skipUids bool) ([]ConnectionStat, error) {
+	conns, err := exConnectionsPidMaxWithContext(ctx, kind, pid, maxConn, skipUids, false)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]ConnectionStat, 0, len(conns))
+	for _, c := range conns {
+		ret = append(ret, c.ConnectionStat)
+	}
+	return ret, nil
+}
+
+func exConnectionsPidMaxWithContext(ctx context.Context, kind string, pid int32, maxConn int, skipUids, tcpInfo bool) ([]ExConnectionStat, error) {
 	tmap, ok := netConnectionKindMap[kind]
 	if !ok {
 		return nil, fmt.Errorf("invalid kind, %s", kind)
@@ -404,46 +589,67 @@ func connectionsPidMaxWithoutUidsWithContext(ctx context.Context, kind string, p
 		inodes, err = getProcInodes(root, pid, maxConn)
 		if len(inodes) == 0 {
 			// no connection for the pid
-			return []ConnectionStat{}, nil
+			return []ExConnectionStat{}, nil
 		}
 	}
 	if err != nil {
 		return nil, fmt.Errorf("could not get pid(s), %d: %w", pid, err)
 	}
-	return statsFromInodesWithContext(ctx, root, pid, tmap, inodes, skipUids)
+	return statsFromInodesWithContext(ctx, root, pid, tmap, inodes, skipUids, tcpInfo)
 }
 
 // connectionDedupKey builds a key to deduplicate connections.
 // For inet sockets, the tuple (type, src, dst, status) is sufficient.
 // For unix sockets, unnamed sockets share the same empty address,
 // so pid, fd, and inode must be included to avoid incorrect deduplication.
+// The same applies to raw, packet and netlink sockets, where many sockets
+// are bound to the same protocol.
 // The inode is especially important when pid/fd are unavailable (e.g.,
 // unprivileged queries where inode-to-pid mapping fails).
 func connectionDedupKey(family uint32, c connTmp) string {
-	if family == syscall.AF_UNIX {
+	if family == syscall.AF_UNIX || family == syscall.AF_PACKET || family == syscall.AF_NETLINK || c.sockType == syscall.SOCK_RAW {
 		return fmt.Sprintf("%d-%d-%s-%d-%s:%d-%s:%d-%s", c.pid, c.fd, c.inode, c.sockType, c.laddr.IP, c.laddr.Port, c.raddr.IP, c.raddr.Port, c.status)
 	}
 	return fmt.Sprintf("%d-%s:%d-%s:%d-%s", c.sockType, c.laddr.IP, c.laddr.Port, c.raddr.IP, c.raddr.Port, c.status)
 }
 
-func statsFromInodesWithContext(ctx context.Context, root string, pid int32, tmap []netConnectionKindType, inodes map[string][]inodeMap, skipUids bool) ([]ConnectionStat, error) {
+// statsFromInodesWithContext returns the connections of tmap. The tcp_info
+// of TCP sockets is only fetched if tcpInfo is true and netlink is available.
+func statsFromInodesWithContext(ctx context.Context, root string, pid int32, tmap []netConnectionKindType, inodes map[string][]inodeMap, skipUids, tcpInfo bool) ([]ExConnectionStat, error) {
 	dupCheckMap := make(map[string]struct{})
-	var ret []ConnectionStat
+	var ret []ExConnectionStat
 
 	var err error
+	sockDiag := useSockDiagWithContext(ctx, pid)
 	for _, t := range tmap {
-		var path string
 		var ls []connTmp
-		if pid == 0 {
-			path = fmt.Sprintf("%s/net/%s", root, t.filename)
-		} else {
-			path = fmt.Sprintf("%s/%d/net/%s", root, pid, t.filename)
+		if sockDiag && sockDiagSupported(t) {
+			ls, err = sockDiagConnections(t, inodes, pid, tcpInfo)
+			if err != nil {
+				// netlink is not available, e.g. restricted by seccomp or
+				// the module is not loaded, fall back to procfs
+				sockDiag = false
+			}
 		}
-		switch t.family {
-		case syscall.AF_INET, syscall.AF_INET6:
-			ls, err = processInet(path, t, inodes, pid)
-		case syscall.AF_UNIX:
-			ls, err = processUnix(path, t, inodes, pid)
+		if !sockDiag || !sockDiagSupported(t) {
+			var path string
+			if pid == 0 {
+				path = hostProcNetWithContext(ctx, t.filename)
+			} else {
+				path = fmt.Sprintf("%s/%d/net/%s", root, pid, t.filename)
+			}
+			switch t.family {
+			case syscall.AF_INET, syscall.AF_INET6:
+				ls, err = processInet(path, t, inodes, pid)
+			case syscall.AF_UNIX:
+				ls, err = processUnix(path, t, inodes, pid)
+			case syscall.AF_PACKET:
+				ls, err = processPacket(path, t, inodes, pid)
+			case syscall.AF_NETLINK:
+				ls, err = processNetlink(path, t, inodes, pid)
+			default:
+				ls, err = processSCTP(path, t, inodes, pid)
+			}
 		}
 		if err != nil {
 			return nil, err
@@ -454,14 +660,19 @@ func statsFromInodesWithContext(ctx context.Context, root string, pid int32, tma
 				continue
 			}
 
-			conn := ConnectionStat{
-				Fd:     c.fd,
-				Family: c.family,
-				Type:   c.sockType,
-				Laddr:  c.laddr,
-				Raddr:  c.raddr,
-				Status: c.status,
-				Pid:    c.pid,
+			conn := ExConnectionStat{
+				ConnectionStat: ConnectionStat{
+					Fd:     c.fd,
+					Family: c.family,
+					Type:   c.sockType,
+					Laddr:  c.laddr,
+					Raddr:  c.raddr,
+					Status: c.status,
+					Pid:    c.pid,
+				},
+				TxQueue: c.txQueue,
+				RxQueue: c.rxQueue,
+				TCPInfo: c.tcpInfo,
 			}
 			if c.pid == 0 {
 				conn.Pid = c.boundPid
@@ -664,6 +875,25 @@ func decodeAddress(family uint32, src string) (Addr, error) {
 	}, nil
 }
 
+// decodeQueues decode the tx_queue:rx_queue column of proc/net/*
+// ex:
+// "00000010:00000200" -> 16, 512
+func decodeQueues(src string) (uint32, uint32, error) {
+	t := strings.Split(src, ":")
+	if len(t) != 2 {
+		return 0, 0, fmt.Errorf("invalid queues, %s", src)
+	}
+	tx, err := strconv.ParseUint(t[0], 16, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid tx_queue, %s", src)
+	}
+	rx, err := strconv.ParseUint(t[1], 16, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid rx_queue, %s", src)
+	}
+	return uint32(tx), uint32(rx), nil
+}
+
 func Reverse(s []byte) []byte {
 	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
 		s[i], s[j] = s[j], s[i]
@@ -712,6 +942,7 @@ func processInet(file string, kind netConnectionKindType, inodes map[string][]in
 		laddr := l[1]
 		raddr := l[2]
 		status := l[3]
+		queues := l[4]
 		inode := l[9]
 		pid := int32(0)
 		fd := uint32(0)
@@ -736,6 +967,10 @@ func processInet(file string, kind netConnectionKindType, inodes map[string][]in
 		if err != nil {
 			continue
 		}
+		txQueue, rxQueue, err := decodeQueues(queues)
+		if err != nil {
+			continue
+		}
 
 		ret = append(ret, connTmp{
 			fd:       fd,
@@ -746,6 +981,8 @@ func processInet(file string, kind netConnectionKindType, inodes map[string][]in
 			status:   status,
 			pid:      pid,
 			inode:    inode,
+			txQueue:  txQueue,
+			rxQueue:  rxQueue,
 		})
 	}
 
@@ -811,6 +1048,288 @@ func processUnix(file string, kind netConnectionKindType, inodes map[string][]in
 	return ret, nil
 }
 
+// sctpStatuses maps enum sctp_state of /proc/net/sctp/assocs.
+var sctpStatuses = map[string]string{
+	"0": "CLOSE",
+	"1": "COOKIE_WAIT",
+	"2": "COOKIE_ECHOED",
+	"3": "ESTABLISHED",
+	"4": "SHUTDOWN_PENDING",
+	"5": "SHUTDOWN_SENT",
+	"6": "SHUTDOWN_RECEIVED",
+	"7": "SHUTDOWN_ACK_SENT",
+}
+
+// processSCTP parses /proc/net/sctp/eps and /proc/net/sctp/assocs. Only the
+// primary address of multi-homed endpoints is returned.
+func processSCTP(file string, kind netConnectionKindType, inodes map[string][]inodeMap, filterPid int32) ([]connTmp, error) {
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// sctp module is not loaded
+			return []connTmp{}, nil
+		}
+		return nil, err
+	}
+
+	assocs := strings.HasSuffix(file, "assocs")
+	lines := bytes.Split(contents, []byte("\n"))
+
+	var ret []connTmp
+	// skip first line
+	for _, line := range lines[1:] {
+		l := strings.Fields(string(line))
+		var sty, state, inode, lport, rport string
+		var laddrs, raddrs []string
+		if assocs {
+			if len(l) < 15 {
+				continue
+			}
+			sty, state, inode, lport, rport = l[2], sctpStatuses[l[4]], l[10], l[11], l[12]
+			i := 13
+			for ; i < len(l) && l[i] != "<->"; i++ {
+				laddrs = append(laddrs, l[i])
+			}
+			// remote addresses are followed by numeric columns
+			for i++; i < len(l); i++ {
+				if _, err := strconv.ParseInt(l[i], 10, 64); err == nil {
+					break
+				}
+				raddrs = append(raddrs, l[i])
+			}
+		} else {
+			if len(l) < 9 {
+				continue
+			}
+			sty, inode, lport = l[2], l[7], l[5]
+			// sk_state uses the TCP states
+			sst, err := strconv.ParseUint(l[3], 10, 8)
+			if err != nil {
+				continue
+			}
+			state = tcpStatuses[fmt.Sprintf("%02X", sst)]
+			laddrs = l[8:]
+		}
+
+		pid := int32(0)
+		fd := uint32(0)
+		i, exists := inodes[inode]
+		if exists {
+			pid = i[0].pid
+			fd = i[0].fd
+		}
+		if filterPid > 0 && filterPid != pid {
+			continue
+		}
+
+		// SCTP_SOCKET_TCP is a one-to-one socket, SCTP_SOCKET_UDP one-to-many
+		sockType := uint32(syscall.SOCK_SEQPACKET)
+		if sty == "2" {
+			sockType = syscall.SOCK_STREAM
+		}
+		if state == "" {
+			state = "NONE"
+		}
+		la, err := decodeSCTPAddress(laddrs, lport)
+		if err != nil {
+			continue
+		}
+		family := uint32(syscall.AF_INET)
+		if strings.Contains(la.IP, ":") {
+			family = syscall.AF_INET6
+		}
+		var ra Addr
+		if assocs {
+			ra, err = decodeSCTPAddress(raddrs, rport)
+			if err != nil {
+				continue
+			}
+		}
+
+		ret = append(ret, connTmp{
+			fd:       fd,
+			family:   family,
+			sockType: sockType,
+			laddr:    la,
+			raddr:    ra,
+			status:   state,
+			pid:      pid,
+			inode:    inode,
+		})
+	}
+
+	return ret, nil
+}
+
+// decodeSCTPAddress returns the primary address of addrs, marked with '*',
+// or the first one.
+func decodeSCTPAddress(addrs []string, port string) (Addr, error) {
+	if len(addrs) == 0 {
+		return Addr{}, errors.New("no address")
+	}
+	addr := addrs[0]
+	for _, a := range addrs {
+		if strings.HasPrefix(a, "*") {
+			addr = a
+			break
+		}
+	}
+	ip := net.ParseIP(strings.TrimPrefix(addr, "*"))
+	if ip == nil {
+		return Addr{}, fmt.Errorf("invalid address, %s", addr)
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return Addr{}, fmt.Errorf("invalid port, %s", port)
+	}
+	return Addr{
+		IP:   ip.String(),
+		Port: uint32(p),
+	}, nil
+}
+
+// processPacket parses /proc/net/packet. Laddr.IP is the index of the bound
+// interface, empty for all interfaces, and Laddr.Port the ethernet protocol.
+func processPacket(file string, kind netConnectionKindType, inodes map[string][]inodeMap, filterPid int32) ([]connTmp, error) {
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// CONFIG_PACKET is not enabled
+			return []connTmp{}, nil
+		}
+		return nil, err
+	}
+
+	lines := bytes.Split(contents, []byte("\n"))
+
+	var ret []connTmp
+	// skip first line
+	for _, line := range lines[1:] {
+		l := strings.Fields(string(line))
+		if len(l) < 9 {
+			continue
+		}
+		st, err := strconv.ParseUint(l[2], 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		proto, err := strconv.ParseUint(l[3], 16, 16)
+		if err != nil {
+			return nil, err
+		}
+		iface := l[4]
+		if iface == "0" {
+			iface = ""
+		}
+		inode := l[8]
+		for _, pair := range inodePairs(inodes, inode) {
+			if filterPid > 0 && filterPid != pair.pid {
+				continue
+			}
+			ret = append(ret, connTmp{
+				fd:       pair.fd,
+				family:   kind.family,
+				sockType: uint32(st),
+				laddr: Addr{
+					IP:   iface,
+					Port: uint32(proto),
+				},
+				pid:    pair.pid,
+				status: "NONE",
+				inode:  inode,
+			})
+		}
+	}
+
+	return ret, nil
+}
+
+// netlinkProtocols maps the protocols of linux/netlink.h to their name.
+var netlinkProtocols = map[string]string{
+	"0":  "route",
+	"1":  "unused",
+	"2":  "usersock",
+	"3":  "firewall",
+	"4":  "sock_diag",
+	"5":  "nflog",
+	"6":  "xfrm",
+	"7":  "selinux",
+	"8":  "iscsi",
+	"9":  "audit",
+	"10": "fib_lookup",
+	"11": "connector",
+	"12": "netfilter",
+	"13": "ip6_fw",
+	"14": "dnrtmsg",
+	"15": "kobject_uevent",
+	"16": "generic",
+	"18": "scsitransport",
+	"19": "ecryptfs",
+	"20": "rdma",
+	"21": "crypto",
+	"22": "smc",
+}
+
+// processNetlink parses /proc/net/netlink. Laddr.IP is the netlink protocol,
+// e.g. "route" or "audit", and Laddr.Port the port id.
+func processNetlink(file string, kind netConnectionKindType, inodes map[string][]inodeMap, filterPid int32) ([]connTmp, error) {
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := bytes.Split(contents, []byte("\n"))
+
+	var ret []connTmp
+	// skip first line
+	for _, line := range lines[1:] {
+		l := strings.Fields(string(line))
+		if len(l) < 10 {
+			continue
+		}
+		proto, ok := netlinkProtocols[l[1]]
+		if !ok {
+			proto = l[1]
+		}
+		portID, err := strconv.ParseUint(l[2], 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		inode := l[9]
+		for _, pair := range inodePairs(inodes, inode) {
+			if filterPid > 0 && filterPid != pair.pid {
+				continue
+			}
+			ret = append(ret, connTmp{
+				fd:       pair.fd,
+				family:   kind.family,
+				sockType: kind.sockType,
+				laddr: Addr{
+					IP:   proto,
+					Port: uint32(portID),
+				},
+				pid:    pair.pid,
+				status: "NONE",
+				inode:  inode,
+			})
+		}
+	}
+
+	return ret, nil
+}
+
+// inodePairs returns the processes holding the socket inode, or a single
+// empty pair if it is unknown.
+func inodePairs(inodes map[string][]inodeMap, inode string) []inodeMap {
+	pairs, exists := inodes[inode]
+	if !exists {
+		pairs = []inodeMap{
+			{},
+		}
+	}
+	return pairs
+}
+
 func updateMap(src, add map[string][]inodeMap) map[string][]inodeMap {
 	for key, value := range add {
 		a, exists := src[key]
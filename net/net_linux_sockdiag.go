@@ -0,0 +1,343 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//go:build linux
+
+package net
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/shirou/gopsutil/v4/internal/common"
+)
+
+// NETLINK_SOCK_DIAG constants from linux/sock_diag.h, linux/inet_diag.h
+// and linux/unix_diag.h.
+const (
+	netlinkSockDiag   = 4
+	sockDiagByFamily  = 20
+	inetDiagReqV2Size = 56
+	inetDiagMsgSize   = 72
+	unixDiagReqSize   = 24
+	unixDiagMsgSize   = 16
+	udiagShowName     = 0x1
+	unixDiagName      = 0
+	inetDiagInfo      = 2
+	// tcpNewSynRecv is the state of request sockets, which /proc/net/tcp
+	// reports as SYN_RECV.
+	tcpNewSynRecv = 12
+	tcpSynRecv    = 3
+	allStates     = 0xffffffff
+)
+
+// useSockDiagWithContext returns true if the connections of pid can be
+// fetched with NETLINK_SOCK_DIAG instead of parsing /proc/net/*. Netlink
+// only sees the network namespace of the caller, so it is not used when
+// HOST_PROC points to another root or pid, or the NetNsPidKey of ctx,
+// lives in another namespace.
+func useSockDiagWithContext(ctx context.Context, pid int32) bool {
+	if common.HostProcWithContext(ctx) != "/proc" {
+		return false
+	}
+	if pid == 0 {
+		pid = netNsPidWithContext(ctx)
+	}
+	if pid == 0 {
+		return true
+	}
+	self, err := os.Readlink("/proc/self/ns/net")
+	if err != nil {
+		return false
+	}
+	other, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/net", pid))
+	if err != nil {
+		return false
+	}
+	return self == other
+}
+
+// sockDiagSupported returns true if the sockets of kind can be fetched with
+// NETLINK_SOCK_DIAG.
+func sockDiagSupported(kind netConnectionKindType) bool {
+	return kind.family == syscall.AF_UNIX || kind.protocol != 0
+}
+
+// sockDiagConnections returns the sockets of kind using NETLINK_SOCK_DIAG.
+// The result is the same as processInet and processUnix would return, with
+// the tcp_info of TCP sockets if tcpInfo is true.
+func sockDiagConnections(kind netConnectionKindType, inodes map[string][]inodeMap, filterPid int32, tcpInfo bool) ([]connTmp, error) {
+	switch kind.family {
+	case syscall.AF_INET, syscall.AF_INET6:
+		if kind.protocol == 0 {
+			return nil, fmt.Errorf("unsupported kind, %s", kind.filename)
+		}
+		return sockDiagInet(kind, inodes, filterPid, tcpInfo)
+	case syscall.AF_UNIX:
+		return sockDiagUnix(kind, inodes, filterPid)
+	}
+	return nil, fmt.Errorf("unsupported family, %d", kind.family)
+}
+
+func sockDiagInet(kind netConnectionKindType, inodes map[string][]inodeMap, filterPid int32, tcpInfo bool) ([]connTmp, error) {
+	req := make([]byte, inetDiagReqV2Size)
+	req[0] = uint8(kind.family)
+	req[1] = kind.protocol
+	binary.NativeEndian.PutUint32(req[4:8], allStates)
+	if tcpInfo && kind.protocol == syscall.IPPROTO_TCP {
+		req[2] = 1 << (inetDiagInfo - 1)
+	}
+
+	var ret []connTmp
+	err := sockDiagDump(req, func(data []byte) error {
+		if len(data) < inetDiagMsgSize {
+			return errors.New("inet_diag message too short")
+		}
+		inode := strconv.FormatUint(uint64(binary.NativeEndian.Uint32(data[68:72])), 10)
+		pid := int32(0)
+		fd := uint32(0)
+		i, exists := inodes[inode]
+		if exists {
+			pid = i[0].pid
+			fd = i[0].fd
+		}
+		if filterPid > 0 && filterPid != pid {
+			return nil
+		}
+		rxQueue := binary.NativeEndian.Uint32(data[56:60])
+		txQueue := binary.NativeEndian.Uint32(data[60:64])
+		status := "NONE"
+		var info *ExTCPInfo
+		if kind.sockType == syscall.SOCK_STREAM {
+			state := data[1]
+			if state == tcpNewSynRecv {
+				state = tcpSynRecv
+			}
+			status = tcpStatuses[fmt.Sprintf("%02X", state)]
+			if status == "LISTEN" {
+				// the maximum backlog, /proc/net/tcp reports 0
+				txQueue = 0
+			}
+			if tcpInfo {
+				attrs, err := parseNetlinkAttrs(data[inetDiagMsgSize:])
+				if err != nil {
+					return err
+				}
+				if b, ok := attrs[inetDiagInfo]; ok {
+					info = parseTCPInfo(b)
+				}
+			}
+		}
+		ret = append(ret, connTmp{
+			fd:       fd,
+			family:   kind.family,
+			sockType: kind.sockType,
+			laddr:    sockDiagAddr(kind.family, data[8:24], data[4:6]),
+			raddr:    sockDiagAddr(kind.family, data[24:40], data[6:8]),
+			status:   status,
+			pid:      pid,
+			inode:    inode,
+			txQueue:  txQueue,
+			rxQueue:  rxQueue,
+			tcpInfo:  info,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// parseTCPInfo decodes a struct tcp_info. Older kernels send a shorter
+// struct, the missing fields are left zero.
+func parseTCPInfo(b []byte) *ExTCPInfo {
+	var raw unix.TCPInfo
+	copy((*[unix.SizeofTCPInfo]byte)(unsafe.Pointer(&raw))[:], b)
+	return &ExTCPInfo{
+		State:         raw.State,
+		CAState:       raw.Ca_state,
+		Retransmits:   raw.Retransmits,
+		RTO:           raw.Rto,
+		SndMSS:        raw.Snd_mss,
+		RcvMSS:        raw.Rcv_mss,
+		Unacked:       raw.Unacked,
+		Sacked:        raw.Sacked,
+		Lost:          raw.Lost,
+		Retrans:       raw.Retrans,
+		LastDataSent:  raw.Last_data_sent,
+		LastDataRecv:  raw.Last_data_recv,
+		LastAckRecv:   raw.Last_ack_recv,
+		PMTU:          raw.Pmtu,
+		RTT:           raw.Rtt,
+		RTTVar:        raw.Rttvar,
+		MinRTT:        raw.Min_rtt,
+		SndSsthresh:   raw.Snd_ssthresh,
+		SndCwnd:       raw.Snd_cwnd,
+		TotalRetrans:  raw.Total_retrans,
+		PacingRate:    raw.Pacing_rate,
+		DeliveryRate:  raw.Delivery_rate,
+		BytesSent:     raw.Bytes_sent,
+		BytesAcked:    raw.Bytes_acked,
+		BytesReceived: raw.Bytes_received,
+		BytesRetrans:  raw.Bytes_retrans,
+		SegsOut:       raw.Segs_out,
+		SegsIn:        raw.Segs_in,
+		NotsentBytes:  raw.Notsent_bytes,
+	}
+}
+
+// sockDiagAddr decodes an address of struct inet_diag_sockid. The port is
+// in network byte order.
+func sockDiagAddr(family uint32, ip, port []byte) Addr {
+	if family == syscall.AF_INET {
+		ip = ip[:net.IPv4len]
+	}
+	return Addr{
+		IP:   net.IP(ip).String(),
+		Port: uint32(binary.BigEndian.Uint16(port)),
+	}
+}
+
+func sockDiagUnix(kind netConnectionKindType, inodes map[string][]inodeMap, filterPid int32) ([]connTmp, error) {
+	req := make([]byte, unixDiagReqSize)
+	req[0] = syscall.AF_UNIX
+	binary.NativeEndian.PutUint32(req[4:8], allStates)
+	binary.NativeEndian.PutUint32(req[12:16], udiagShowName)
+
+	var ret []connTmp
+	err := sockDiagDump(req, func(data []byte) error {
+		if len(data) < unixDiagMsgSize {
+			return errors.New("unix_diag message too short")
+		}
+		inode := strconv.FormatUint(uint64(binary.NativeEndian.Uint32(data[4:8])), 10)
+
+		var path string
+		attrs, err := parseNetlinkAttrs(data[unixDiagMsgSize:])
+		if err != nil {
+			return err
+		}
+		if name, ok := attrs[unixDiagName]; ok && len(name) > 0 {
+			if name[0] != 0 {
+				// path names include the terminating NUL
+				if i := bytes.IndexByte(name, 0); i >= 0 {
+					name = name[:i]
+				}
+			}
+			// abstract sockets are shown with '@' as in /proc/net/unix
+			path = strings.ReplaceAll(string(name), "\x00", "@")
+		}
+
+		pairs, exists := inodes[inode]
+		if !exists {
+			pairs = []inodeMap{
+				{},
+			}
+		}
+		for _, pair := range pairs {
+			if filterPid > 0 && filterPid != pair.pid {
+				continue
+			}
+			ret = append(ret, connTmp{
+				fd:       pair.fd,
+				family:   kind.family,
+				sockType: uint32(data[1]),
+				laddr: Addr{
+					IP: path,
+				},
+				pid:    pair.pid,
+				status: "NONE",
+				path:   path,
+				inode:  inode,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// sockDiagDump sends a SOCK_DIAG_BY_FAMILY dump request and calls fn with
+// the payload of every message of the response.
+func sockDiagDump(req []byte, fn func(data []byte) error) error {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, netlinkSockDiag)
+	if err != nil {
+		return err
+	}
+	defer syscall.Close(fd)
+
+	msg := make([]byte, syscall.NLMSG_HDRLEN+len(req))
+	binary.NativeEndian.PutUint32(msg[0:4], uint32(len(msg)))
+	binary.NativeEndian.PutUint16(msg[4:6], sockDiagByFamily)
+	binary.NativeEndian.PutUint16(msg[6:8], syscall.NLM_F_REQUEST|syscall.NLM_F_DUMP)
+	binary.NativeEndian.PutUint32(msg[8:12], 1)
+	copy(msg[syscall.NLMSG_HDRLEN:], req)
+	if err := syscall.Sendto(fd, msg, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 32*os.Getpagesize())
+	for {
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			if errors.Is(err, syscall.EINTR) {
+				continue
+			}
+			return err
+		}
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			switch m.Header.Type {
+			case syscall.NLMSG_DONE:
+				return nil
+			case syscall.NLMSG_ERROR:
+				if len(m.Data) < 4 {
+					return errors.New("netlink error message too short")
+				}
+				errno := int32(binary.NativeEndian.Uint32(m.Data[0:4]))
+				if errno == 0 {
+					return nil
+				}
+				return syscall.Errno(-errno)
+			}
+			if err := fn(m.Data); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parseNetlinkAttrs returns the payload of the netlink attributes in b by
+// their type.
+func parseNetlinkAttrs(b []byte) (map[uint16][]byte, error) {
+	attrs := make(map[uint16][]byte)
+	for len(b) >= syscall.SizeofRtAttr {
+		l := int(binary.NativeEndian.Uint16(b[0:2]))
+		// mask NLA_F_NESTED and NLA_F_NET_BYTEORDER
+		typ := binary.NativeEndian.Uint16(b[2:4]) & 0x3fff
+		if l < syscall.SizeofRtAttr || l > len(b) {
+			return nil, errors.New("invalid netlink attribute length")
+		}
+		attrs[typ] = b[syscall.SizeofRtAttr:l]
+		l = (l + syscall.RTA_ALIGNTO - 1) & ^(syscall.RTA_ALIGNTO - 1)
+		if l > len(b) {
+			break
+		}
+		b = b[l:]
+	}
+	return attrs, nil
+}
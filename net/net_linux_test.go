@@ -442,3 +442,219 @@ entries  searched found new invalid ignore delete deleteList insert insertFailed
 		assert.Equal(t, 0, i) // Should only have one element
 	}
 }
+
+func TestProtoCountersExtended(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux/proc")
+
+	v, err := ProtoCountersWithContext(context.Background(), nil)
+	require.NoError(t, err)
+
+	protos := make(map[string]ProtoCountersStat, len(v))
+	for _, p := range v {
+		protos[p.Protocol] = p
+	}
+	for _, p := range []string{"ip", "tcp", "udp", "tcpext", "ipext", "ip6", "icmp6", "udp6", "udplite6"} {
+		assert.Containsf(t, protos, p, "missing protocol %s", p)
+	}
+	assert.NotContains(t, protos, "mptcpext")
+
+	assert.Equal(t, int64(3), protos["tcpext"].Stats["SyncookiesSent"])
+	assert.Equal(t, int64(12), protos["tcpext"].Stats["ListenDrops"])
+	assert.Equal(t, int64(4), protos["tcpext"].Stats["TCPBacklogDrop"])
+	assert.Equal(t, int64(20534032), protos["ipext"].Stats["InOctets"])
+	assert.Equal(t, int64(1524), protos["ip6"].Stats["InReceives"])
+	assert.Equal(t, int64(20), protos["icmp6"].Stats["InType135"])
+	assert.Equal(t, int64(310), protos["udp6"].Stats["InDatagrams"])
+	assert.Equal(t, int64(2), protos["udp6"].Stats["NoPorts"])
+	assert.Len(t, protos["udplite6"].Stats, 3)
+}
+
+func TestProtoCountersSelected(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux/proc")
+
+	v, err := ProtoCountersWithContext(context.Background(), []string{"tcpext", "udp6"})
+	require.NoError(t, err)
+	require.Len(t, v, 2)
+	assert.Equal(t, "tcpext", v[0].Protocol)
+	assert.Equal(t, "udp6", v[1].Protocol)
+	assert.Len(t, v[1].Stats, 4)
+}
+
+func TestSockDiagConnections(t *testing.T) {
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	port := uint32(l.Addr().(*net.TCPAddr).Port)
+
+	ls, err := sockDiagConnections(kindTCP4, nil, 0, false)
+	if err != nil {
+		t.Skipf("NETLINK_SOCK_DIAG not available: %v", err)
+	}
+	procfs, err := processInet("/proc/net/tcp", kindTCP4, nil, 0)
+	require.NoError(t, err)
+
+	find := func(conns []connTmp) *connTmp {
+		for _, c := range conns {
+			if c.laddr.Port == port && c.status == "LISTEN" {
+				return &c
+			}
+		}
+		return nil
+	}
+	c := find(ls)
+	require.NotNilf(t, c, "listener on port %d not found", port)
+	expected := find(procfs)
+	require.NotNil(t, expected)
+	assert.Equal(t, *expected, *c)
+	assert.Equal(t, "127.0.0.1", c.laddr.IP)
+	assert.Equal(t, "0.0.0.0", c.raddr.IP)
+}
+
+func TestSockDiagConnectionsUnix(t *testing.T) {
+	path := fmt.Sprintf("%s/gopsutil-%d.sock", t.TempDir(), os.Getpid())
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	defer l.Close()
+
+	ls, err := sockDiagConnections(kindUNIX, nil, 0, false)
+	if err != nil {
+		t.Skipf("NETLINK_SOCK_DIAG not available: %v", err)
+	}
+	var found bool
+	for _, c := range ls {
+		if c.path == path {
+			found = true
+			assert.Equal(t, uint32(syscall.SOCK_STREAM), c.sockType)
+			assert.Equal(t, path, c.laddr.IP)
+		}
+	}
+	assert.Truef(t, found, "unix socket %s not found", path)
+}
+
+func TestExConnectionsQueues(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux/proc")
+
+	v, err := NewExLinux().Connections("tcp4")
+	require.NoError(t, err)
+	require.Len(t, v, 2)
+
+	assert.Equal(t, "LISTEN", v[0].Status)
+	assert.Equal(t, uint32(80), v[0].Laddr.Port)
+	assert.Equal(t, uint32(0), v[0].TxQueue)
+	assert.Equal(t, uint32(3), v[0].RxQueue)
+
+	assert.Equal(t, "ESTABLISHED", v[1].Status)
+	assert.Equal(t, Addr{IP: "127.0.0.1", Port: 50000}, v[1].Raddr)
+	assert.Equal(t, uint32(4096), v[1].TxQueue)
+	assert.Equal(t, uint32(512), v[1].RxQueue)
+	assert.Nil(t, v[1].TCPInfo)
+}
+
+func TestSockDiagTCPInfo(t *testing.T) {
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	c, err := net.Dial("tcp4", l.Addr().String())
+	require.NoError(t, err)
+	defer c.Close()
+	_, err = c.Write([]byte("gopsutil"))
+	require.NoError(t, err)
+	localPort := uint32(c.LocalAddr().(*net.TCPAddr).Port)
+
+	ls, err := sockDiagConnections(kindTCP4, nil, 0, true)
+	if err != nil {
+		t.Skipf("NETLINK_SOCK_DIAG not available: %v", err)
+	}
+	var found bool
+	for _, conn := range ls {
+		if conn.laddr.Port != localPort {
+			continue
+		}
+		found = true
+		require.NotNil(t, conn.tcpInfo)
+		assert.Equal(t, uint8(1), conn.tcpInfo.State)
+		assert.NotZero(t, conn.tcpInfo.SndMSS)
+		assert.NotZero(t, conn.tcpInfo.SndCwnd)
+	}
+	assert.Truef(t, found, "connection from port %d not found", localPort)
+}
+
+func TestConnectionsOtherKinds(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux/proc")
+	ctx := context.Background()
+
+	raw, err := ConnectionsWithoutUidsWithContext(ctx, "raw4")
+	require.NoError(t, err)
+	require.Len(t, raw, 2, "raw sockets with the same address must not be deduplicated")
+	assert.Equal(t, uint32(syscall.SOCK_RAW), raw[0].Type)
+	assert.Equal(t, Addr{IP: "0.0.0.0", Port: 1}, raw[0].Laddr)
+	assert.Equal(t, "NONE", raw[0].Status)
+
+	sctp, err := ConnectionsWithoutUidsWithContext(ctx, "sctp")
+	require.NoError(t, err)
+	require.Len(t, sctp, 3)
+	assert.Equal(t, ConnectionStat{
+		Family: syscall.AF_INET,
+		Type:   syscall.SOCK_STREAM,
+		Laddr:  Addr{IP: "10.0.0.1", Port: 11111},
+		Status: "LISTEN",
+	}, sctp[0])
+	assert.Equal(t, ConnectionStat{
+		Family: syscall.AF_INET6,
+		Type:   syscall.SOCK_SEQPACKET,
+		Laddr:  Addr{IP: "fd00::1", Port: 22222},
+		Status: "LISTEN",
+	}, sctp[1])
+	assert.Equal(t, ConnectionStat{
+		Family: syscall.AF_INET,
+		Type:   syscall.SOCK_STREAM,
+		Laddr:  Addr{IP: "10.0.0.2", Port: 11111},
+		Raddr:  Addr{IP: "10.0.0.3", Port: 33333},
+		Status: "ESTABLISHED",
+	}, sctp[2])
+
+	packet, err := ConnectionsWithoutUidsWithContext(ctx, "packet")
+	require.NoError(t, err)
+	require.Len(t, packet, 2)
+	assert.Equal(t, uint32(syscall.AF_PACKET), packet[0].Family)
+	assert.Equal(t, uint32(syscall.SOCK_RAW), packet[0].Type)
+	assert.Equal(t, Addr{IP: "", Port: 0x0003}, packet[0].Laddr)
+	assert.Equal(t, uint32(syscall.SOCK_DGRAM), packet[1].Type)
+	assert.Equal(t, Addr{IP: "2", Port: 0x88cc}, packet[1].Laddr)
+
+	netlink, err := ConnectionsWithoutUidsWithContext(ctx, "netlink")
+	require.NoError(t, err)
+	require.Len(t, netlink, 3)
+	assert.Equal(t, uint32(syscall.AF_NETLINK), netlink[1].Family)
+	assert.Equal(t, Addr{IP: "route", Port: 1234}, netlink[1].Laddr)
+	assert.Equal(t, Addr{IP: "audit", Port: 1235}, netlink[2].Laddr)
+}
+
+func TestNetNsPidKey(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux/proc")
+	ctx := context.WithValue(context.Background(), NetNsPidKey, int32(4321))
+
+	counters, err := IOCountersWithContext(ctx, true)
+	require.NoError(t, err)
+	require.Len(t, counters, 2)
+	assert.Equal(t, "eth0", counters[1].Name)
+	assert.Equal(t, uint64(8345012), counters[1].BytesRecv)
+	assert.Equal(t, uint64(3), counters[1].Dropin)
+
+	conns, err := ConnectionsWithoutUidsWithContext(ctx, "tcp4")
+	require.NoError(t, err)
+	require.Len(t, conns, 1)
+	assert.Equal(t, Addr{IP: "10.17.0.2", Port: 8080}, conns[0].Laddr)
+	assert.Equal(t, "LISTEN", conns[0].Status)
+}
+
+func TestExNetNamespaces(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux/proc")
+
+	v, err := NewExLinux().NetNamespaces()
+	require.NoError(t, err)
+	assert.Equal(t, []ExNetNamespace{
+		{Inode: 4026531840, Pids: []int32{4323}},
+		{Inode: 4026532451, Pids: []int32{4321, 4322}},
+	}, v)
+}
@@ -5,12 +5,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"regexp"
 	"runtime"
 	"sort"
 	"sync"
 	"time"
 
+	"github.com/shirou/gopsutil/v4/cgroup"
 	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/shirou/gopsutil/v4/internal/common"
 	"github.com/shirou/gopsutil/v4/mem"
@@ -44,6 +46,12 @@ type Process struct {
 	lastCPUTime  time.Time
 
 	tgid int32
+
+	// pidfd is opened by OpenPidfd on Linux, it is -1 if the kernel does
+	// not support pidfd.
+	pidfdMutex sync.Mutex
+	pidfd      int
+	pidfdOpen  bool
 }
 
 // Process status
@@ -79,6 +87,41 @@ type OpenFilesStat struct {
 	Fd   uint64 `json:"fd"`
 }
 
+// FileDescriptorType is the kind of file a file descriptor refers to.
+type FileDescriptorType string
+
+const (
+	FileDescriptorFile   FileDescriptorType = "file"
+	FileDescriptorDir    FileDescriptorType = "dir"
+	FileDescriptorSocket FileDescriptorType = "socket"
+	FileDescriptorPipe   FileDescriptorType = "pipe"
+	FileDescriptorDevice FileDescriptorType = "device"
+	// FileDescriptorAnonInode is a file without a path, created by
+	// eventfd(2), epoll_create(2), timerfd_create(2), inotify_init(2) and
+	// the like. Its kind is in FileDescriptorStat.AnonInodeType.
+	FileDescriptorAnonInode FileDescriptorType = "anon_inode"
+	// FileDescriptorOther is any other file, such as a namespace.
+	FileDescriptorOther FileDescriptorType = "other"
+)
+
+// FileDescriptorStat is a file descriptor opened by a process.
+type FileDescriptorStat struct {
+	Fd uint64 `json:"fd"`
+	// Path is the path of the file, or a description such as
+	// socket:[12345] for the files without a path.
+	Path string             `json:"path"`
+	Type FileDescriptorType `json:"type"`
+	// AnonInodeType is the kind of a FileDescriptorAnonInode, such as
+	// eventfd, eventpoll, timerfd or inotify.
+	AnonInodeType string `json:"anonInodeType"`
+	Inode         uint64 `json:"inode"`
+	// Pos is the file offset, Flags holds the O_* flags of open(2) and
+	// MntID is the ID of the mount of the file.
+	Pos   int64  `json:"pos"`
+	Flags uint32 `json:"flags"`
+	MntID int32  `json:"mntId"`
+}
+
 type MemoryInfoStat struct {
 	RSS    uint64 `json:"rss"`    // bytes
 	VMS    uint64 `json:"vms"`    // bytes
@@ -133,6 +176,151 @@ type PageFaultsStat struct {
 	ChildMajorFaults uint64 `json:"childMajorFaults"`
 }
 
+// MemoryFullInfoStat adds to MemoryInfoStat the values which need to walk
+// the memory mappings of the process. USS is the memory private to the
+// process, which would be freed if it exited. PSS is its share of the
+// memory, the pages shared by N processes counting for 1/N each.
+type MemoryFullInfoStat struct {
+	RSS  uint64 `json:"rss"`  // bytes
+	VMS  uint64 `json:"vms"`  // bytes
+	USS  uint64 `json:"uss"`  // bytes
+	PSS  uint64 `json:"pss"`  // bytes
+	Swap uint64 `json:"swap"` // bytes
+}
+
+// ThreadInfoStat holds the details of a thread. CPU is the CPU the thread
+// last ran on. Wchan is the kernel function the thread is sleeping in, it
+// is empty when the thread is running or the kernel hides it.
+type ThreadInfoStat struct {
+	Tid            int32               `json:"tid"`
+	Name           string              `json:"name"`
+	Status         string              `json:"status"`
+	CPU            int32               `json:"cpu"`
+	CPUTimes       *cpu.TimesStat      `json:"cpuTimes"`
+	NumCtxSwitches *NumCtxSwitchesStat `json:"numCtxSwitches"`
+	PageFaults     *PageFaultsStat     `json:"pageFaults"`
+	Wchan          string              `json:"wchan"`
+}
+
+// IOPriorityClass is the I/O scheduling class of a process, see ioprio_set(2).
+type IOPriorityClass int32
+
+const (
+	// IOPriorityClassNone means no class was set, the priority is derived
+	// from the nice value.
+	IOPriorityClassNone IOPriorityClass = 0
+	// IOPriorityClassRT is the real time class, served first.
+	IOPriorityClassRT IOPriorityClass = 1
+	// IOPriorityClassBE is the best effort class, the default.
+	IOPriorityClassBE IOPriorityClass = 2
+	// IOPriorityClassIdle is only served when no other process uses the disk.
+	IOPriorityClassIdle IOPriorityClass = 3
+)
+
+func (c IOPriorityClass) String() string {
+	switch c {
+	case IOPriorityClassNone:
+		return "none"
+	case IOPriorityClassRT:
+		return "rt"
+	case IOPriorityClassBE:
+		return "be"
+	case IOPriorityClassIdle:
+		return "idle"
+	}
+	return fmt.Sprintf("IOPriorityClass(%d)", int32(c))
+}
+
+// IOPriorityStat is the I/O priority of a process. Level goes from 0, the
+// highest priority, to 7 for the RT and BE classes, and is 0 otherwise.
+type IOPriorityStat struct {
+	Class IOPriorityClass `json:"class"`
+	Level int32           `json:"level"`
+}
+
+// SchedulingPolicy is the CPU scheduling policy of a process, see sched(7).
+type SchedulingPolicy int32
+
+const (
+	// SchedOther is the default time sharing policy.
+	SchedOther SchedulingPolicy = 0
+	// SchedFIFO and SchedRR are the real time policies, which run before
+	// the processes of the other policies.
+	SchedFIFO SchedulingPolicy = 1
+	SchedRR   SchedulingPolicy = 2
+	// SchedBatch is for CPU intensive processes, preempted less often.
+	SchedBatch SchedulingPolicy = 3
+	// SchedIdle only runs when no other process is runnable.
+	SchedIdle SchedulingPolicy = 5
+	// SchedDeadline runs the process within the deadline set by
+	// sched_setattr(2).
+	SchedDeadline SchedulingPolicy = 6
+)
+
+func (s SchedulingPolicy) String() string {
+	switch s {
+	case SchedOther:
+		return "other"
+	case SchedFIFO:
+		return "fifo"
+	case SchedRR:
+		return "rr"
+	case SchedBatch:
+		return "batch"
+	case SchedIdle:
+		return "idle"
+	case SchedDeadline:
+		return "deadline"
+	}
+	return fmt.Sprintf("SchedulingPolicy(%d)", int32(s))
+}
+
+// SchedulerStat is the scheduling of a process. RTPriority goes from 1 to 99
+// for the SchedFIFO and SchedRR policies, and is 0 otherwise. RunTime is the
+// time spent on a CPU and WaitTime the time spent runnable waiting for a CPU,
+// both in nanoseconds, over Timeslices runs on a CPU. A WaitTime growing
+// faster than RunTime shows a process starved of CPU.
+type SchedulerStat struct {
+	Policy     SchedulingPolicy `json:"policy"`
+	RTPriority int32            `json:"rtPriority"`
+	RunTime    uint64           `json:"runTime"`
+	WaitTime   uint64           `json:"waitTime"`
+	Timeslices uint64           `json:"timeslices"`
+}
+
+func (s SchedulerStat) String() string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// NamespacesStat holds the namespaces of a process, by the inode number of
+// their /proc/<pid>/ns link. Processes with the same inode number are in the
+// same namespace. The inode number is 0 for the namespaces the kernel does
+// not support.
+type NamespacesStat struct {
+	Cgroup          uint64 `json:"cgroup"`
+	IPC             uint64 `json:"ipc"`
+	Mnt             uint64 `json:"mnt"`
+	Net             uint64 `json:"net"`
+	Pid             uint64 `json:"pid"`
+	PidForChildren  uint64 `json:"pidForChildren"`
+	Time            uint64 `json:"time"`
+	TimeForChildren uint64 `json:"timeForChildren"`
+	User            uint64 `json:"user"`
+	UTS             uint64 `json:"uts"`
+	// NSpid and NStgid are the pid and the thread group id of the process
+	// in each nested pid namespace, from the one of the caller to the one of
+	// the process, such as the pid of a containerized process on the host
+	// then in the container.
+	NSpid  []int32 `json:"nspid"`
+	NStgid []int32 `json:"nstgid"`
+}
+
+func (n NamespacesStat) String() string {
+	s, _ := json.Marshal(n)
+	return string(s)
+}
+
 // Resource limit constants are from /usr/include/x86_64-linux-gnu/bits/resource.h
 // from libc6-dev package in Ubuntu 16.10
 const (
@@ -164,6 +352,11 @@ func (o OpenFilesStat) String() string {
 	return string(s)
 }
 
+func (f FileDescriptorStat) String() string {
+	s, _ := json.Marshal(f)
+	return string(s)
+}
+
 func (m MemoryInfoStat) String() string {
 	s, _ := json.Marshal(m)
 	return string(s)
@@ -184,6 +377,21 @@ func (p NumCtxSwitchesStat) String() string {
 	return string(s)
 }
 
+func (m MemoryFullInfoStat) String() string {
+	s, _ := json.Marshal(m)
+	return string(s)
+}
+
+func (t ThreadInfoStat) String() string {
+	s, _ := json.Marshal(t)
+	return string(s)
+}
+
+func (i IOPriorityStat) String() string {
+	s, _ := json.Marshal(i)
+	return string(s)
+}
+
 var enableBootTimeCache bool
 
 // EnableBootTimeCache change cache behavior of BootTime. If true, cache BootTime value. Default is false.
@@ -387,6 +595,12 @@ func (p *Process) Groups() ([]uint32, error) {
 	return p.GroupsWithContext(context.Background())
 }
 
+// SecurityContext returns the capabilities, seccomp mode, tracer and security
+// module label of the process. It is only implemented on Linux.
+func (p *Process) SecurityContext() (*SecurityContextStat, error) {
+	return p.SecurityContextWithContext(context.Background())
+}
+
 // Ppid returns Parent Process ID of the process.
 func (p *Process) Ppid() (int32, error) {
 	return p.PpidWithContext(context.Background())
@@ -472,11 +686,44 @@ func (p *Process) Nice() (int32, error) {
 	return p.NiceWithContext(context.Background())
 }
 
-// IOnice returns process I/O nice value (priority).
+// IOnice returns process I/O nice value (priority). On Linux this is the
+// value of ioprio_get(2), see IOPriority for its class and level.
 func (p *Process) IOnice() (int32, error) {
 	return p.IOniceWithContext(context.Background())
 }
 
+// Scheduler returns the scheduling policy and real time priority of the
+// process, with the time it ran and waited for a CPU. It is only implemented
+// on Linux.
+func (p *Process) Scheduler() (*SchedulerStat, error) {
+	return p.SchedulerWithContext(context.Background())
+}
+
+// IOPriority returns the I/O scheduling class and level of the process.
+func (p *Process) IOPriority() (*IOPriorityStat, error) {
+	return p.IOPriorityWithContext(context.Background())
+}
+
+// SetIOnice sets the I/O scheduling class and level of the process. Raising
+// the priority, or using the RT class, needs privileges.
+func (p *Process) SetIOnice(class IOPriorityClass, level int32) error {
+	return p.SetIOniceWithContext(context.Background(), class, level)
+}
+
+// SetNice sets the nice value of the process. Lowering it needs privileges,
+// the returned error then wraps os.ErrPermission.
+func (p *Process) SetNice(nice int32) error {
+	return p.SetNiceWithContext(context.Background(), nice)
+}
+
+// SetOOMScoreAdj sets the value, from -1000 to 1000, added to the badness
+// score of the process when the OOM killer picks a process to kill.
+// Lowering it needs privileges, the returned error then wraps
+// os.ErrPermission.
+func (p *Process) SetOOMScoreAdj(adj int32) error {
+	return p.SetOOMScoreAdjWithContext(context.Background(), adj)
+}
+
 // Rlimit returns Resource Limits.
 func (p *Process) Rlimit() ([]RlimitStat, error) {
 	return p.RlimitWithContext(context.Background())
@@ -489,6 +736,14 @@ func (p *Process) RlimitUsage(gatherUsed bool) ([]RlimitStat, error) {
 	return p.RlimitUsageWithContext(context.Background(), gatherUsed)
 }
 
+// SetRlimit sets the soft and hard limits of resource, one of the RLIMIT_*
+// constants, and returns the previous limits. Raising the hard limit, or
+// changing the limits of a process of another user, needs privileges, the
+// returned error then wraps os.ErrPermission.
+func (p *Process) SetRlimit(resource int32, soft, hard uint64) (*RlimitStat, error) {
+	return p.SetRlimitWithContext(context.Background(), resource, soft, hard)
+}
+
 // IOCounters returns IO Counters.
 func (p *Process) IOCounters() (*IOCountersStat, error) {
 	return p.IOCountersWithContext(context.Background())
@@ -513,6 +768,12 @@ func (p *Process) Threads() (map[int32]*cpu.TimesStat, error) {
 	return p.ThreadsWithContext(context.Background())
 }
 
+// ThreadsInfo returns the details of the threads of the process, ordered by
+// tid. Threads which exit while they are read are skipped.
+func (p *Process) ThreadsInfo() ([]ThreadInfoStat, error) {
+	return p.ThreadsInfoWithContext(context.Background())
+}
+
 // Times returns CPU times of the process.
 func (p *Process) Times() (*cpu.TimesStat, error) {
 	return p.TimesWithContext(context.Background())
@@ -523,17 +784,49 @@ func (p *Process) CPUAffinity() ([]int32, error) {
 	return p.CPUAffinityWithContext(context.Background())
 }
 
+// SetCPUAffinity restricts the process to run on cpus. On Linux only the
+// main thread is changed, like `taskset -p`, threads created afterwards
+// inherit its affinity. Use SetThreadCPUAffinity for the other threads.
+func (p *Process) SetCPUAffinity(cpus []int32) error {
+	return p.SetCPUAffinityWithContext(context.Background(), cpus)
+}
+
+// ThreadCPUAffinity returns CPU affinity of the thread tid of the process.
+func (p *Process) ThreadCPUAffinity(tid int32) ([]int32, error) {
+	return p.ThreadCPUAffinityWithContext(context.Background(), tid)
+}
+
+// SetThreadCPUAffinity restricts the thread tid of the process to run on
+// cpus.
+func (p *Process) SetThreadCPUAffinity(tid int32, cpus []int32) error {
+	return p.SetThreadCPUAffinityWithContext(context.Background(), tid, cpus)
+}
+
 // MemoryInfo returns generic process memory information,
 // such as RSS and VMS.
 func (p *Process) MemoryInfo() (*MemoryInfoStat, error) {
 	return p.MemoryInfoWithContext(context.Background())
 }
 
+// MemoryFullInfo returns the memory information of MemoryInfo with the USS,
+// PSS and swap of the process. It is slower than MemoryInfo, and may need
+// privileges for processes of other users.
+func (p *Process) MemoryFullInfo() (*MemoryFullInfoStat, error) {
+	return p.MemoryFullInfoWithContext(context.Background())
+}
+
 // MemoryInfoEx returns platform-specific process memory information.
 func (p *Process) MemoryInfoEx() (*MemoryInfoExStat, error) {
 	return p.MemoryInfoExWithContext(context.Background())
 }
 
+// MemoryInfoStatus returns the memory usage of /proc/<pid>/status, which
+// splits the resident memory into anonymous, file and shared memory pages.
+// It is only implemented on Linux.
+func (p *Process) MemoryInfoStatus() (*MemoryInfoStatusStat, error) {
+	return p.MemoryInfoStatusWithContext(context.Background())
+}
+
 // PageFaults returns the process's page fault counters.
 func (p *Process) PageFaults() (*PageFaultsStat, error) {
 	return p.PageFaultsWithContext(context.Background())
@@ -551,6 +844,25 @@ func (p *Process) OpenFiles() ([]OpenFilesStat, error) {
 	return p.OpenFilesWithContext(context.Background())
 }
 
+// FileDescriptors returns every file descriptor opened by the process, with
+// the kind of file it refers to. It is only implemented on Linux, where the
+// offset, flags and mount ID come from /proc/<pid>/fdinfo.
+func (p *Process) FileDescriptors() ([]FileDescriptorStat, error) {
+	return p.FileDescriptorsWithContext(context.Background())
+}
+
+// Namespaces returns the namespaces of the process, and its pid in each pid
+// namespace. It is only implemented on Linux.
+func (p *Process) Namespaces() (*NamespacesStat, error) {
+	return p.NamespacesWithContext(context.Background())
+}
+
+// Cgroups returns the cgroups of the process, from /proc/<pid>/cgroup. It
+// is only implemented on Linux.
+func (p *Process) Cgroups() ([]cgroup.Membership, error) {
+	return p.CgroupsWithContext(context.Background())
+}
+
 // Connections returns a slice of net.ConnectionStat used by the process.
 // This returns all kind of the connection. This means TCP, UDP or UNIX.
 func (p *Process) Connections() ([]net.ConnectionStat, error) {
@@ -597,6 +909,51 @@ func (p *Process) Kill() error {
 	return p.KillWithContext(context.Background())
 }
 
+// OpenPidfd opens a pidfd for the process, so that SendSignal, Suspend,
+// Resume, Terminate and Kill can not reach another process which reused the
+// pid. The create time of the process is checked once the pidfd is opened.
+// On kernels without pidfd, before 5.3, the create time is checked before
+// sending each signal instead. This is only available on Linux, the pidfd
+// must be released with ClosePidfd.
+func (p *Process) OpenPidfd() error {
+	return p.OpenPidfdWithContext(context.Background())
+}
+
+// Wait blocks until the process exits. It uses a pidfd on Linux, and polls
+// the process, checking its create time, on other platforms. Unlike
+// os.Process.Wait, the process does not need to be a child, and it is not
+// reaped.
+func (p *Process) Wait() error {
+	return p.WaitWithContext(context.Background())
+}
+
+// waitPollInterval is how often Wait checks the process without pidfd.
+var waitPollInterval = 100 * time.Millisecond
+
+// waitPollWithContext waits for the process to exit by checking it every
+// waitPollInterval. A zombie is considered as exited.
+func (p *Process) waitPollWithContext(ctx context.Context) error {
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+	for {
+		running, err := p.IsRunningWithContext(ctx)
+		if err != nil && !isProcessGone(err) {
+			return err
+		}
+		if !running {
+			return nil
+		}
+		if status, err := p.StatusWithContext(ctx); err == nil && len(status) > 0 && status[0] == Zombie {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
 // Username returns a username of the process.
 func (p *Process) Username() (string, error) {
 	return p.UsernameWithContext(context.Background())
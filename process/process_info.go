@@ -0,0 +1,274 @@
+// SPDX-License-Identifier: BSD-3-Clause
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+)
+
+// Attr is a field of ProcessInfo which can be selected with ProcessesInfo.
+type Attr string
+
+const (
+	AttrName           Attr = "name"
+	AttrPpid           Attr = "ppid"
+	AttrCmdline        Attr = "cmdline"
+	AttrExe            Attr = "exe"
+	AttrCwd            Attr = "cwd"
+	AttrStatus         Attr = "status"
+	AttrCreateTime     Attr = "create_time"
+	AttrCPUTimes       Attr = "cpu_times"
+	AttrMemoryInfo     Attr = "memory_info"
+	AttrUsername       Attr = "username"
+	AttrUids           Attr = "uids"
+	AttrGids           Attr = "gids"
+	AttrNumThreads     Attr = "num_threads"
+	AttrNice           Attr = "nice"
+	AttrNumCtxSwitches Attr = "num_ctx_switches"
+	AttrTerminal       Attr = "terminal"
+	AttrNumFDs         Attr = "num_fds"
+	AttrIOCounters     Attr = "io_counters"
+)
+
+// AllAttrs lists every Attr, which is what ProcessesInfo collects when no
+// attribute is given.
+var AllAttrs = []Attr{
+	AttrName, AttrPpid, AttrCmdline, AttrExe, AttrCwd, AttrStatus,
+	AttrCreateTime, AttrCPUTimes, AttrMemoryInfo, AttrUsername, AttrUids,
+	AttrGids, AttrNumThreads, AttrNice, AttrNumCtxSwitches, AttrTerminal,
+	AttrNumFDs, AttrIOCounters,
+}
+
+// ProcessInfo holds the attributes of a process read by ProcessesInfo.
+// Attributes which were not requested, or could not be read, such as the
+// exe of a process owned by another user, are left to their zero value.
+type ProcessInfo struct {
+	Pid            int32               `json:"pid"`
+	Name           string              `json:"name"`
+	Ppid           int32               `json:"ppid"`
+	Cmdline        []string            `json:"cmdline"`
+	Exe            string              `json:"exe"`
+	Cwd            string              `json:"cwd"`
+	Status         []string            `json:"status"`
+	CreateTime     int64               `json:"createTime"`
+	CPUTimes       *cpu.TimesStat      `json:"cpuTimes"`
+	MemoryInfo     *MemoryInfoStat     `json:"memoryInfo"`
+	Username       string              `json:"username"`
+	Uids           []uint32            `json:"uids"`
+	Gids           []uint32            `json:"gids"`
+	NumThreads     int32               `json:"numThreads"`
+	Nice           int32               `json:"nice"`
+	NumCtxSwitches *NumCtxSwitchesStat `json:"numCtxSwitches"`
+	Terminal       string              `json:"terminal"`
+	NumFDs         int32               `json:"numFds"`
+	IOCounters     *IOCountersStat     `json:"ioCounters"`
+}
+
+func (p ProcessInfo) String() string {
+	s, _ := json.Marshal(p)
+	return string(s)
+}
+
+// ProcessesInfo reads attrs of all running processes, ordered by pid. All
+// attributes are read if attrs is empty. On Linux each file of /proc/<pid>
+// is read at most once per process. Processes which exit during the scan
+// are not returned.
+func ProcessesInfo(attrs []Attr) ([]ProcessInfo, error) {
+	return ProcessesInfoWithContext(context.Background(), attrs)
+}
+
+func ProcessesInfoWithContext(ctx context.Context, attrs []Attr) ([]ProcessInfo, error) {
+	wanted, err := attrSet(attrs)
+	if err != nil {
+		return nil, err
+	}
+	pids, err := PidsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &processInfoCache{usernames: make(map[uint32]string)}
+	ret := make([]ProcessInfo, 0, len(pids))
+	for _, pid := range pids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		info, err := processInfoWithContext(ctx, pid, wanted, c)
+		if err != nil {
+			// the process exited
+			continue
+		}
+		ret = append(ret, *info)
+	}
+	return ret, nil
+}
+
+func attrSet(attrs []Attr) (map[Attr]bool, error) {
+	if len(attrs) == 0 {
+		attrs = AllAttrs
+	}
+	known := make(map[Attr]bool, len(AllAttrs))
+	for _, a := range AllAttrs {
+		known[a] = true
+	}
+	ret := make(map[Attr]bool, len(attrs))
+	for _, a := range attrs {
+		if !known[a] {
+			return nil, fmt.Errorf("unknown process attribute: %s", a)
+		}
+		ret[a] = true
+	}
+	return ret, nil
+}
+
+// processInfoCache holds the values shared by all processes of a scan.
+type processInfoCache struct {
+	termmap       map[uint64]string
+	usernames     map[uint32]string
+	bootTimeValue uint64
+	bootTimeRead  bool
+}
+
+// isProcessGone returns true if err means that the process does not exist
+// anymore.
+func isProcessGone(err error) bool {
+	return errors.Is(err, ErrorProcessNotRunning) || errors.Is(err, os.ErrNotExist)
+}
+
+// genericProcessInfoWithContext reads the attributes of a process with the
+// Process methods, for platforms without a faster way. An error is only
+// returned if the process does not exist.
+func genericProcessInfoWithContext(ctx context.Context, pid int32, wanted map[Attr]bool) (*ProcessInfo, error) {
+	p := &Process{Pid: pid}
+	info := &ProcessInfo{Pid: pid}
+	failed := false
+	check := func(err error) bool {
+		if err != nil {
+			failed = true
+			return false
+		}
+		return true
+	}
+
+	if wanted[AttrName] {
+		name, err := p.NameWithContext(ctx)
+		if check(err) {
+			info.Name = name
+		}
+	}
+	if wanted[AttrPpid] {
+		ppid, err := p.PpidWithContext(ctx)
+		if check(err) {
+			info.Ppid = ppid
+		}
+	}
+	if wanted[AttrCmdline] {
+		cmdline, err := p.CmdlineSliceWithContext(ctx)
+		if check(err) {
+			info.Cmdline = cmdline
+		}
+	}
+	if wanted[AttrExe] {
+		exe, err := p.ExeWithContext(ctx)
+		if check(err) {
+			info.Exe = exe
+		}
+	}
+	if wanted[AttrCwd] {
+		cwd, err := p.CwdWithContext(ctx)
+		if check(err) {
+			info.Cwd = cwd
+		}
+	}
+	if wanted[AttrStatus] {
+		status, err := p.StatusWithContext(ctx)
+		if check(err) {
+			info.Status = status
+		}
+	}
+	if wanted[AttrCreateTime] {
+		createTime, err := p.CreateTimeWithContext(ctx)
+		if check(err) {
+			info.CreateTime = createTime
+		}
+	}
+	if wanted[AttrCPUTimes] {
+		times, err := p.TimesWithContext(ctx)
+		if check(err) {
+			info.CPUTimes = times
+		}
+	}
+	if wanted[AttrMemoryInfo] {
+		mem, err := p.MemoryInfoWithContext(ctx)
+		if check(err) {
+			info.MemoryInfo = mem
+		}
+	}
+	if wanted[AttrUsername] {
+		username, err := p.UsernameWithContext(ctx)
+		if check(err) {
+			info.Username = username
+		}
+	}
+	if wanted[AttrUids] {
+		uids, err := p.UidsWithContext(ctx)
+		if check(err) {
+			info.Uids = uids
+		}
+	}
+	if wanted[AttrGids] {
+		gids, err := p.GidsWithContext(ctx)
+		if check(err) {
+			info.Gids = gids
+		}
+	}
+	if wanted[AttrNumThreads] {
+		numThreads, err := p.NumThreadsWithContext(ctx)
+		if check(err) {
+			info.NumThreads = numThreads
+		}
+	}
+	if wanted[AttrNice] {
+		nice, err := p.NiceWithContext(ctx)
+		if check(err) {
+			info.Nice = nice
+		}
+	}
+	if wanted[AttrNumCtxSwitches] {
+		ctxSwitches, err := p.NumCtxSwitchesWithContext(ctx)
+		if check(err) {
+			info.NumCtxSwitches = ctxSwitches
+		}
+	}
+	if wanted[AttrTerminal] {
+		terminal, err := p.TerminalWithContext(ctx)
+		if check(err) {
+			info.Terminal = terminal
+		}
+	}
+	if wanted[AttrNumFDs] {
+		numFDs, err := p.NumFDsWithContext(ctx)
+		if check(err) {
+			info.NumFDs = numFDs
+		}
+	}
+	if wanted[AttrIOCounters] {
+		io, err := p.IOCountersWithContext(ctx)
+		if check(err) {
+			info.IOCounters = io
+		}
+	}
+
+	if failed {
+		exists, err := PidExistsWithContext(ctx, pid)
+		if err == nil && !exists {
+			return nil, ErrorProcessNotRunning
+		}
+	}
+	return info, nil
+}
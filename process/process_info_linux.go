@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//go:build linux
+
+package process
+
+import (
+	"context"
+	"os"
+	"os/user"
+	"strconv"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/internal/common"
+)
+
+// processInfoWithContext reads the attributes of a process, reading each
+// file of /proc/<pid> at most once. An error is only returned if the
+// process does not exist.
+func processInfoWithContext(ctx context.Context, pid int32, wanted map[Attr]bool, c *processInfoCache) (*ProcessInfo, error) {
+	p := &Process{Pid: pid}
+	info := &ProcessInfo{Pid: pid}
+	failed := false
+	check := func(err error) bool {
+		if err != nil {
+			failed = true
+			return false
+		}
+		return true
+	}
+
+	// cmdline is also needed to extend a long name read from status, keep
+	// it to not read it twice
+	var cmdline []string
+	var cmdlineErr error
+	cmdlineRead := false
+	cmdlineSlice := func(ctx context.Context) ([]string, error) {
+		if !cmdlineRead {
+			cmdline, cmdlineErr = p.fillSliceFromCmdlineWithContext(ctx)
+			cmdlineRead = true
+		}
+		return cmdline, cmdlineErr
+	}
+
+	if wanted[AttrPpid] || wanted[AttrCreateTime] || wanted[AttrCPUTimes] || wanted[AttrNice] || wanted[AttrTerminal] {
+		contents, err := os.ReadFile(common.HostProcWithContext(ctx, strconv.Itoa(int(pid)), "stat"))
+		if err == nil && len(contents) == 0 {
+			err = ErrorProcessNotRunning
+		}
+		var terminal uint64
+		var ppid, nice int32
+		var times *cpu.TimesStat
+		var createTime int64
+		if err == nil {
+			terminal, ppid, times, createTime, _, nice, _, err = p.fillFromStatContents(contents, c.bootTime(ctx))
+		}
+		if check(err) {
+			if wanted[AttrPpid] {
+				info.Ppid = ppid
+			}
+			if wanted[AttrCreateTime] {
+				info.CreateTime = createTime
+			}
+			if wanted[AttrCPUTimes] {
+				info.CPUTimes = times
+			}
+			if wanted[AttrNice] {
+				info.Nice = nice
+			}
+			if wanted[AttrTerminal] {
+				info.Terminal = c.terminal(terminal)
+			}
+		} else if isProcessGone(err) {
+			return nil, ErrorProcessNotRunning
+		}
+	}
+
+	if wanted[AttrName] || wanted[AttrStatus] || wanted[AttrUids] || wanted[AttrGids] || wanted[AttrUsername] || wanted[AttrNumThreads] || wanted[AttrNumCtxSwitches] {
+		contents, err := os.ReadFile(common.HostProcWithContext(ctx, strconv.Itoa(int(pid)), "status"))
+		if err == nil {
+			extend := cmdlineSlice
+			if !wanted[AttrName] {
+				// skip the name extension, which reads cmdline
+				extend = func(context.Context) ([]string, error) { return nil, nil }
+			}
+			err = p.fillFromStatusContentsWithContext(ctx, contents, extend)
+		}
+		if check(err) {
+			if wanted[AttrName] {
+				info.Name = p.name
+			}
+			if wanted[AttrStatus] {
+				info.Status = []string{p.status}
+			}
+			if wanted[AttrUids] {
+				info.Uids = p.uids
+			}
+			if wanted[AttrGids] {
+				info.Gids = p.gids
+			}
+			if wanted[AttrNumThreads] {
+				info.NumThreads = p.numThreads
+			}
+			if wanted[AttrNumCtxSwitches] {
+				info.NumCtxSwitches = p.numCtxSwitches
+			}
+			if wanted[AttrUsername] && len(p.uids) > 0 {
+				info.Username = c.username(p.uids[0])
+			}
+		} else if isProcessGone(err) {
+			return nil, ErrorProcessNotRunning
+		}
+	}
+
+	if wanted[AttrCmdline] {
+		if slice, err := cmdlineSlice(ctx); check(err) {
+			info.Cmdline = slice
+		}
+	}
+	if wanted[AttrMemoryInfo] {
+		if mem, _, err := p.fillFromStatmWithContext(ctx); check(err) {
+			info.MemoryInfo = mem
+		}
+	}
+	if wanted[AttrExe] {
+		if exe, err := p.fillFromExeWithContext(ctx); check(err) {
+			info.Exe = exe
+		}
+	}
+	if wanted[AttrCwd] {
+		if cwd, err := p.fillFromCwdWithContext(ctx); check(err) {
+			info.Cwd = cwd
+		}
+	}
+	if wanted[AttrNumFDs] {
+		if _, fnames, err := p.fillFromfdListWithContext(ctx); check(err) {
+			info.NumFDs = int32(len(fnames))
+		}
+	}
+	if wanted[AttrIOCounters] {
+		if io, err := p.fillFromIOWithContext(ctx); check(err) {
+			info.IOCounters = io
+		}
+	}
+
+	if failed {
+		// the links of kernel threads, or the files of processes of another
+		// user, fail while the process is still there
+		if _, err := os.Stat(common.HostProcWithContext(ctx, strconv.Itoa(int(pid)))); isProcessGone(err) {
+			return nil, ErrorProcessNotRunning
+		}
+	}
+	return info, nil
+}
+
+// terminal returns the name of the tty number of /proc/<pid>/stat, reading
+// /dev only once per scan.
+func (c *processInfoCache) terminal(tty uint64) string {
+	if c.termmap == nil {
+		termmap, err := getTerminalMap()
+		if err != nil {
+			termmap = make(map[uint64]string)
+		}
+		c.termmap = termmap
+	}
+	return c.termmap[tty]
+}
+
+// bootTime returns the boot time, reading /proc/stat only once per scan.
+func (c *processInfoCache) bootTime(ctx context.Context) uint64 {
+	if !c.bootTimeRead {
+		c.bootTimeValue, _ = bootTimeWithContext(ctx, enableBootTimeCache)
+		c.bootTimeRead = true
+	}
+	return c.bootTimeValue
+}
+
+// username returns the name of uid, looking it up only once per scan.
+func (c *processInfoCache) username(uid uint32) string {
+	name, ok := c.usernames[uid]
+	if !ok {
+		if u, err := user.LookupId(strconv.Itoa(int(uid))); err == nil {
+			name = u.Username
+		}
+		c.usernames[uid] = name
+	}
+	return name
+}
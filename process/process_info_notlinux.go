@@ -0,0 +1,12 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//go:build !linux
+
+package process
+
+import (
+	"context"
+)
+
+func processInfoWithContext(ctx context.Context, pid int32, wanted map[Attr]bool, _ *processInfoCache) (*ProcessInfo, error) {
+	return genericProcessInfoWithContext(ctx, pid, wanted)
+}
@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"os"
@@ -19,6 +20,7 @@ import (
 	"github.com/tklauser/go-sysconf"
 	"golang.org/x/sys/unix"
 
+	"github.com/shirou/gopsutil/v4/cgroup"
 	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/shirou/gopsutil/v4/internal/common"
 	"github.com/shirou/gopsutil/v4/net"
@@ -28,6 +30,12 @@ var pageSize = uint64(os.Getpagesize())
 
 const prioProcess = 0 // linux/resource.h
 
+// bootTimeWithContext is replaced by the tests counting the reads of
+// /proc/stat.
+var bootTimeWithContext = common.BootTimeWithContext
+
+const cpuSetSize = 1024 // CPU_SETSIZE of sched.h, the CPUs held by unix.CPUSet
+
 var clockTicks = 100 // default value
 
 func init() {
@@ -54,6 +62,34 @@ func (m MemoryInfoExStat) String() string {
 	return string(s)
 }
 
+// MemoryInfoStatusStat is the memory usage reported by /proc/<pid>/status.
+// RSS is the sum of RSSAnon, RSSFile and RSSShmem, which tells the growth of
+// the anonymous memory from the file pages mapped by the process. All fields
+// are zero for kernel threads.
+type MemoryInfoStatusStat struct {
+	VMPeak       uint64 `json:"vmPeak"`       // bytes
+	VMS          uint64 `json:"vms"`          // bytes
+	Locked       uint64 `json:"locked"`       // bytes
+	Pinned       uint64 `json:"pinned"`       // bytes
+	HWM          uint64 `json:"hwm"`          // bytes
+	RSS          uint64 `json:"rss"`          // bytes
+	RSSAnon      uint64 `json:"rssAnon"`      // bytes
+	RSSFile      uint64 `json:"rssFile"`      // bytes
+	RSSShmem     uint64 `json:"rssShmem"`     // bytes
+	Data         uint64 `json:"data"`         // bytes
+	Stack        uint64 `json:"stack"`        // bytes
+	Text         uint64 `json:"text"`         // bytes
+	Lib          uint64 `json:"lib"`          // bytes
+	PTE          uint64 `json:"pte"`          // bytes
+	Swap         uint64 `json:"swap"`         // bytes
+	HugetlbPages uint64 `json:"hugetlbPages"` // bytes
+}
+
+func (m MemoryInfoStatusStat) String() string {
+	s, _ := json.Marshal(m)
+	return string(s)
+}
+
 type MemoryMapsStat struct {
 	Path         string `json:"path"`
 	Rss          uint64 `json:"rss"`
@@ -66,6 +102,18 @@ type MemoryMapsStat struct {
 	Referenced   uint64 `json:"referenced"`
 	Anonymous    uint64 `json:"anonymous"`
 	Swap         uint64 `json:"swap"`
+	// StartAddr, EndAddr, Perms, Offset, Dev and Inode come from the
+	// header line of the mapping, they are not set when grouped.
+	StartAddr     uint64 `json:"startAddr"`
+	EndAddr       uint64 `json:"endAddr"`
+	Perms         string `json:"perms"`
+	Offset        uint64 `json:"offset"`
+	Dev           string `json:"dev"`
+	Inode         uint64 `json:"inode"`
+	LazyFree      uint64 `json:"lazyFree"`
+	AnonHugePages uint64 `json:"anonHugePages"`
+	Locked        uint64 `json:"locked"`
+	SwapPss       uint64 `json:"swapPss"`
 }
 
 // String returns JSON value of the process.
@@ -173,6 +221,70 @@ func (p *Process) GroupsWithContext(ctx context.Context) ([]uint32, error) {
 	return p.groups, nil
 }
 
+func (p *Process) SecurityContextWithContext(ctx context.Context) (*SecurityContextStat, error) {
+	pid := strconv.Itoa(int(p.Pid))
+	contents, err := os.ReadFile(common.HostProcWithContext(ctx, pid, "status"))
+	if err != nil {
+		return nil, err
+	}
+	ret, err := parseSecurityContextStatus(contents)
+	if err != nil {
+		return nil, err
+	}
+	// reading it fails with EINVAL when no security module labels the
+	// processes
+	label, err := os.ReadFile(common.HostProcWithContext(ctx, pid, "attr", "current"))
+	if err == nil {
+		ret.Label = strings.TrimRight(string(label), "\x00\n")
+	}
+	return ret, nil
+}
+
+// parseSecurityContextStatus parses the security lines of /proc/(pid)/status.
+// Seccomp is missing when the kernel is built without seccomp.
+func parseSecurityContextStatus(contents []byte) (*SecurityContextStat, error) {
+	ret := &SecurityContextStat{}
+	caps := map[string]*CapabilitySet{
+		"CapInh": &ret.CapInh,
+		"CapPrm": &ret.CapPrm,
+		"CapEff": &ret.CapEff,
+		"CapBnd": &ret.CapBnd,
+		"CapAmb": &ret.CapAmb,
+	}
+	for _, line := range strings.Split(string(contents), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if c, ok := caps[key]; ok {
+			v, err := strconv.ParseUint(value, 16, 64)
+			if err != nil {
+				return nil, err
+			}
+			*c = CapabilitySet(v)
+			continue
+		}
+		switch key {
+		case "Seccomp":
+			v, err := strconv.ParseInt(value, 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			ret.Seccomp = SeccompMode(v)
+		case "NoNewPrivs":
+			ret.NoNewPrivs = value == "1"
+		case "TracerPid":
+			v, err := strconv.ParseInt(value, 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			ret.TracerPid = int32(v)
+		}
+	}
+	return ret, nil
+}
+
 func (p *Process) TerminalWithContext(ctx context.Context) (string, error) {
 	t, _, _, _, _, _, _, err := p.fillFromStatWithContext(ctx)
 	if err != nil {
@@ -194,8 +306,106 @@ func (p *Process) NiceWithContext(ctx context.Context) (int32, error) {
 	return nice, nil
 }
 
-func (*Process) IOniceWithContext(_ context.Context) (int32, error) {
-	return 0, common.ErrNotImplementedError
+func (p *Process) IOniceWithContext(_ context.Context) (int32, error) {
+	return ioprioGet(p.Pid)
+}
+
+func (p *Process) SchedulerWithContext(ctx context.Context) (*SchedulerStat, error) {
+	pid := strconv.Itoa(int(p.Pid))
+	contents, err := os.ReadFile(common.HostProcWithContext(ctx, pid, "stat"))
+	if err != nil {
+		return nil, err
+	}
+	// Indexing from one, as described in `man proc` about the file /proc/[pid]/stat
+	fields := splitProcStat(contents)
+	if len(fields) < 42 {
+		return nil, fmt.Errorf("malformed stat file: expected at least 42 fields, got %d", len(fields))
+	}
+	rtPriority, err := strconv.ParseInt(fields[40], 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := strconv.ParseInt(fields[41], 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	ret := &SchedulerStat{
+		Policy:     SchedulingPolicy(policy),
+		RTPriority: int32(rtPriority),
+	}
+
+	// missing when the kernel is built without CONFIG_SCHED_INFO
+	contents, err = os.ReadFile(common.HostProcWithContext(ctx, pid, "schedstat"))
+	if errors.Is(err, os.ErrNotExist) {
+		return ret, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	fields = strings.Fields(string(contents))
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("malformed schedstat file: expected 3 fields, got %d", len(fields))
+	}
+	values := make([]uint64, 3)
+	for i := range values {
+		if values[i], err = strconv.ParseUint(fields[i], 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	ret.RunTime, ret.WaitTime, ret.Timeslices = values[0], values[1], values[2]
+	return ret, nil
+}
+
+func (p *Process) IOPriorityWithContext(_ context.Context) (*IOPriorityStat, error) {
+	ioprio, err := ioprioGet(p.Pid)
+	if err != nil {
+		return nil, err
+	}
+	return &IOPriorityStat{
+		Class: IOPriorityClass(ioprio >> ioprioClassShift),
+		Level: ioprio & (1<<ioprioClassShift - 1),
+	}, nil
+}
+
+func (p *Process) SetIOniceWithContext(_ context.Context, class IOPriorityClass, level int32) error {
+	switch class {
+	case IOPriorityClassRT, IOPriorityClassBE:
+		if level < 0 || level > 7 {
+			return fmt.Errorf("invalid I/O priority level for class %s: %d", class, level)
+		}
+	case IOPriorityClassNone, IOPriorityClassIdle:
+		if level != 0 {
+			return fmt.Errorf("I/O priority class %s has no level", class)
+		}
+	default:
+		return fmt.Errorf("invalid I/O priority class: %d", int32(class))
+	}
+	ioprio := int32(class)<<ioprioClassShift | level
+	_, _, errno := unix.Syscall(unix.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(p.Pid), uintptr(ioprio))
+	if errno != 0 {
+		if errno == unix.ESRCH {
+			return ErrorProcessNotRunning
+		}
+		return errno
+	}
+	return nil
+}
+
+// ioprio_get(2) and ioprio_set(2) constants from linux/ioprio.h
+const (
+	ioprioWhoProcess = 1
+	ioprioClassShift = 13
+)
+
+func ioprioGet(pid int32) (int32, error) {
+	r, _, errno := unix.Syscall(unix.SYS_IOPRIO_GET, ioprioWhoProcess, uintptr(pid), 0)
+	if errno != 0 {
+		if errno == unix.ESRCH {
+			return 0, ErrorProcessNotRunning
+		}
+		return 0, errno
+	}
+	return int32(r), nil
 }
 
 func (p *Process) RlimitWithContext(ctx context.Context) ([]RlimitStat, error) {
@@ -257,6 +467,57 @@ func (p *Process) RlimitUsageWithContext(ctx context.Context, gatherUsed bool) (
 	return rlimits, err
 }
 
+func (p *Process) SetRlimitWithContext(_ context.Context, resource int32, soft, hard uint64) (*RlimitStat, error) {
+	if soft > hard {
+		return nil, fmt.Errorf("soft limit %d is above hard limit %d", soft, hard)
+	}
+	limit := unix.Rlimit{Cur: soft, Max: hard}
+	var old unix.Rlimit
+	if err := unix.Prlimit(int(p.Pid), int(resource), &limit, &old); err != nil {
+		return nil, p.setError(fmt.Sprintf("resource limit %d", resource), err)
+	}
+	return &RlimitStat{
+		Resource: resource,
+		Soft:     old.Cur,
+		Hard:     old.Max,
+	}, nil
+}
+
+func (p *Process) SetNiceWithContext(_ context.Context, nice int32) error {
+	if err := unix.Setpriority(prioProcess, int(p.Pid), int(nice)); err != nil {
+		return p.setError("nice", err)
+	}
+	return nil
+}
+
+func (p *Process) SetOOMScoreAdjWithContext(ctx context.Context, adj int32) error {
+	if adj < -1000 || adj > 1000 {
+		return fmt.Errorf("invalid OOM score adjustment: %d", adj)
+	}
+	path := common.HostProcWithContext(ctx, strconv.Itoa(int(p.Pid)), "oom_score_adj")
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return p.setError("OOM score adjustment", err)
+	}
+	_, err = f.WriteString(strconv.Itoa(int(adj)))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return p.setError("OOM score adjustment", err)
+	}
+	return nil
+}
+
+// setError returns ErrorProcessNotRunning if err means that the process does
+// not exist, or else err with the process and the changed value.
+func (p *Process) setError(what string, err error) error {
+	if errors.Is(err, unix.ESRCH) || errors.Is(err, os.ErrNotExist) {
+		return ErrorProcessNotRunning
+	}
+	return fmt.Errorf("cannot set %s of process %d: %w", what, p.Pid, err)
+}
+
 func (p *Process) IOCountersWithContext(ctx context.Context) (*IOCountersStat, error) {
 	return p.fillFromIOWithContext(ctx)
 }
@@ -302,6 +563,101 @@ func (p *Process) ThreadsWithContext(ctx context.Context) (map[int32]*cpu.TimesS
 	return ret, nil
 }
 
+func (p *Process) ThreadsInfoWithContext(ctx context.Context) ([]ThreadInfoStat, error) {
+	taskPath := common.HostProcWithContext(ctx, strconv.Itoa(int(p.Pid)), "task")
+	tids, err := readPidsFromDir(taskPath)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(tids, func(i, j int) bool { return tids[i] < tids[j] })
+
+	ret := make([]ThreadInfoStat, 0, len(tids))
+	for _, tid := range tids {
+		tidPath := filepath.Join(taskPath, strconv.Itoa(int(tid)))
+		contents, err := os.ReadFile(filepath.Join(tidPath, "stat"))
+		if err != nil {
+			if os.IsNotExist(err) || errors.Is(err, unix.ESRCH) {
+				// the thread exited
+				continue
+			}
+			return nil, err
+		}
+		t, err := parseThreadStat(contents)
+		if err != nil {
+			return nil, err
+		}
+		if comm, err := os.ReadFile(filepath.Join(tidPath, "comm")); err == nil {
+			t.Name = strings.TrimSuffix(string(comm), "\n")
+		}
+		// the context switches of a thread are only in its status
+		thread := &Process{Pid: tid}
+		if contents, err := os.ReadFile(filepath.Join(tidPath, "status")); err == nil {
+			noCmdline := func(context.Context) ([]string, error) { return nil, nil }
+			if err := thread.fillFromStatusContentsWithContext(ctx, contents, noCmdline); err == nil {
+				t.NumCtxSwitches = thread.numCtxSwitches
+			}
+		}
+		if wchan, err := os.ReadFile(filepath.Join(tidPath, "wchan")); err == nil && string(wchan) != "0" {
+			t.Wchan = string(wchan)
+		}
+		ret = append(ret, *t)
+	}
+	return ret, nil
+}
+
+// parseThreadStat parses the content of /proc/(pid)/task/(tid)/stat.
+func parseThreadStat(contents []byte) (*ThreadInfoStat, error) {
+	fields := splitProcStat(contents)
+	if len(fields) < 40 {
+		return nil, fmt.Errorf("malformed stat file: expected at least 40 fields, got %d", len(fields))
+	}
+	tid, err := strconv.ParseInt(fields[1], 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	var faults [4]uint64
+	for i := range faults {
+		faults[i], err = strconv.ParseUint(fields[10+i], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+	utime, err := strconv.ParseFloat(fields[14], 64)
+	if err != nil {
+		return nil, err
+	}
+	stime, err := strconv.ParseFloat(fields[15], 64)
+	if err != nil {
+		return nil, err
+	}
+	processor, err := strconv.ParseInt(fields[39], 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	var iotime float64
+	if len(fields) > 42 {
+		iotime, _ = strconv.ParseFloat(fields[42], 64)
+	}
+	return &ThreadInfoStat{
+		Tid:    int32(tid),
+		Name:   fields[2],
+		Status: convertStatusChar(fields[3]),
+		CPU:    int32(processor),
+		CPUTimes: &cpu.TimesStat{
+			CPU:    "cpu",
+			User:   utime / float64(clockTicks),
+			System: stime / float64(clockTicks),
+			Iowait: iotime / float64(clockTicks),
+		},
+		PageFaults: &PageFaultsStat{
+			MinorFaults:      faults[0],
+			ChildMinorFaults: faults[1],
+			MajorFaults:      faults[2],
+			ChildMajorFaults: faults[3],
+		},
+	}, nil
+}
+
 func (p *Process) TimesWithContext(ctx context.Context) (*cpu.TimesStat, error) {
 	_, _, cpuTimes, _, _, _, _, err := p.fillFromStatWithContext(ctx)
 	if err != nil {
@@ -310,8 +666,76 @@ func (p *Process) TimesWithContext(ctx context.Context) (*cpu.TimesStat, error)
 	return cpuTimes, nil
 }
 
-func (*Process) CPUAffinityWithContext(_ context.Context) ([]int32, error) {
-	return nil, common.ErrNotImplementedError
+func (p *Process) CPUAffinityWithContext(_ context.Context) ([]int32, error) {
+	return getCPUAffinity(p.Pid)
+}
+
+func (p *Process) SetCPUAffinityWithContext(_ context.Context, cpus []int32) error {
+	return setCPUAffinity(p.Pid, cpus)
+}
+
+func (p *Process) ThreadCPUAffinityWithContext(ctx context.Context, tid int32) ([]int32, error) {
+	if err := p.checkThreadWithContext(ctx, tid); err != nil {
+		return nil, err
+	}
+	return getCPUAffinity(tid)
+}
+
+func (p *Process) SetThreadCPUAffinityWithContext(ctx context.Context, tid int32, cpus []int32) error {
+	if err := p.checkThreadWithContext(ctx, tid); err != nil {
+		return err
+	}
+	return setCPUAffinity(tid, cpus)
+}
+
+// checkThreadWithContext returns ErrorProcessNotRunning if tid is not a
+// thread of the process.
+func (p *Process) checkThreadWithContext(ctx context.Context, tid int32) error {
+	taskPath := common.HostProcWithContext(ctx, strconv.Itoa(int(p.Pid)), "task", strconv.Itoa(int(tid)))
+	if _, err := os.Stat(taskPath); err != nil {
+		if os.IsNotExist(err) {
+			return ErrorProcessNotRunning
+		}
+		return err
+	}
+	return nil
+}
+
+func getCPUAffinity(tid int32) ([]int32, error) {
+	var set unix.CPUSet
+	if err := unix.SchedGetaffinity(int(tid), &set); err != nil {
+		if errors.Is(err, unix.ESRCH) {
+			return nil, ErrorProcessNotRunning
+		}
+		return nil, err
+	}
+	ret := make([]int32, 0, set.Count())
+	for i := 0; i < cpuSetSize; i++ {
+		if set.IsSet(i) {
+			ret = append(ret, int32(i))
+		}
+	}
+	return ret, nil
+}
+
+func setCPUAffinity(tid int32, cpus []int32) error {
+	if len(cpus) == 0 {
+		return errors.New("no CPU given")
+	}
+	var set unix.CPUSet
+	for _, c := range cpus {
+		if c < 0 || c >= cpuSetSize {
+			return fmt.Errorf("invalid CPU: %d", c)
+		}
+		set.Set(int(c))
+	}
+	if err := unix.SchedSetaffinity(int(tid), &set); err != nil {
+		if errors.Is(err, unix.ESRCH) {
+			return ErrorProcessNotRunning
+		}
+		return err
+	}
+	return nil
 }
 
 func (p *Process) MemoryInfoWithContext(ctx context.Context) (*MemoryInfoStat, error) {
@@ -330,6 +754,74 @@ func (p *Process) MemoryInfoExWithContext(ctx context.Context) (*MemoryInfoExSta
 	return memInfoEx, nil
 }
 
+func (p *Process) MemoryInfoStatusWithContext(ctx context.Context) (*MemoryInfoStatusStat, error) {
+	contents, err := os.ReadFile(common.HostProcWithContext(ctx, strconv.Itoa(int(p.Pid)), "status"))
+	if err != nil {
+		return nil, err
+	}
+	return parseMemoryInfoStatus(contents)
+}
+
+// parseMemoryInfoStatus parses the memory lines of /proc/(pid)/status.
+func parseMemoryInfoStatus(contents []byte) (*MemoryInfoStatusStat, error) {
+	ret := &MemoryInfoStatusStat{}
+	fields := map[string]*uint64{
+		"VmPeak":       &ret.VMPeak,
+		"VmSize":       &ret.VMS,
+		"VmLck":        &ret.Locked,
+		"VmPin":        &ret.Pinned,
+		"VmHWM":        &ret.HWM,
+		"VmRSS":        &ret.RSS,
+		"RssAnon":      &ret.RSSAnon,
+		"RssFile":      &ret.RSSFile,
+		"RssShmem":     &ret.RSSShmem,
+		"VmData":       &ret.Data,
+		"VmStk":        &ret.Stack,
+		"VmExe":        &ret.Text,
+		"VmLib":        &ret.Lib,
+		"VmPTE":        &ret.PTE,
+		"VmSwap":       &ret.Swap,
+		"HugetlbPages": &ret.HugetlbPages,
+	}
+	for _, line := range strings.Split(string(contents), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		field, ok := fields[key]
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(strings.TrimSuffix(value, " kB"))
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		*field = v * 1024
+	}
+	return ret, nil
+}
+
+func (p *Process) MemoryFullInfoWithContext(ctx context.Context) (*MemoryFullInfoStat, error) {
+	meminfo, _, err := p.fillFromStatmWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// grouped maps are read from smaps_rollup when available
+	maps, err := p.MemoryMapsWithContext(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	rollup := (*maps)[0]
+	return &MemoryFullInfoStat{
+		RSS:  meminfo.RSS,
+		VMS:  meminfo.VMS,
+		USS:  (rollup.PrivateClean + rollup.PrivateDirty) * 1024,
+		PSS:  rollup.Pss * 1024,
+		Swap: rollup.Swap * 1024,
+	}, nil
+}
+
 func (p *Process) PageFaultsWithContext(ctx context.Context) (*PageFaultsStat, error) {
 	_, _, _, _, _, _, pageFaults, err := p.fillFromStatWithContext(ctx)
 	if err != nil {
@@ -389,19 +881,22 @@ func (p *Process) ConnectionsMaxWithContext(ctx context.Context, maxConn int) ([
 func (p *Process) MemoryMapsWithContext(ctx context.Context, grouped bool) (*[]MemoryMapsStat, error) {
 	pid := p.Pid
 	var ret []MemoryMapsStat
-	smapsPath := common.HostProcWithContext(ctx, strconv.Itoa(int(pid)), "smaps")
+	var contents []byte
+	var err error
 	if grouped {
 		ret = make([]MemoryMapsStat, 1)
 		// If smaps_rollup exists (require kernel >= 4.15), then we will use it
 		// for pre-summed memory information for a process.
-		smapsRollupPath := common.HostProcWithContext(ctx, strconv.Itoa(int(pid)), "smaps_rollup")
-		if _, err := os.Stat(smapsRollupPath); !os.IsNotExist(err) {
-			smapsPath = smapsRollupPath
+		contents, err = os.ReadFile(common.HostProcWithContext(ctx, strconv.Itoa(int(pid)), "smaps_rollup"))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
 		}
 	}
-	contents, err := os.ReadFile(smapsPath)
-	if err != nil {
-		return nil, err
+	if contents == nil {
+		contents, err = os.ReadFile(common.HostProcWithContext(ctx, strconv.Itoa(int(pid)), "smaps"))
+		if err != nil {
+			return nil, err
+		}
 	}
 	lines := strings.Split(string(contents), "\n")
 
@@ -411,6 +906,11 @@ func (p *Process) MemoryMapsWithContext(ctx context.Context, grouped bool) (*[]M
 		if len(firstLine) >= 6 {
 			m.Path = strings.Join(firstLine[5:], " ")
 		}
+		if !grouped {
+			if err := parseMemoryMapHeader(firstLine, &m); err != nil {
+				return m, err
+			}
+		}
 
 		for _, line := range block {
 			if strings.Contains(line, "VmFlags") {
@@ -447,6 +947,14 @@ func (p *Process) MemoryMapsWithContext(ctx context.Context, grouped bool) (*[]M
 				m.Anonymous = t
 			case "Swap":
 				m.Swap = t
+			case "LazyFree":
+				m.LazyFree = t
+			case "AnonHugePages":
+				m.AnonHugePages = t
+			case "Locked":
+				m.Locked = t
+			case "SwapPss":
+				m.SwapPss = t
 			}
 		}
 		return m, nil
@@ -475,6 +983,10 @@ func (p *Process) MemoryMapsWithContext(ctx context.Context, grouped bool) (*[]M
 					ret[0].Referenced += g.Referenced
 					ret[0].Anonymous += g.Anonymous
 					ret[0].Swap += g.Swap
+					ret[0].LazyFree += g.LazyFree
+					ret[0].AnonHugePages += g.AnonHugePages
+					ret[0].Locked += g.Locked
+					ret[0].SwapPss += g.SwapPss
 				} else {
 					ret = append(ret, g)
 				}
@@ -490,6 +1002,35 @@ func (p *Process) MemoryMapsWithContext(ctx context.Context, grouped bool) (*[]M
 	return &ret, nil
 }
 
+// parseMemoryMapHeader parses the fields of the first line of a mapping in
+// /proc/(pid)/smaps, such as
+// "7f3c1a200000-7f3c1a222000 r--p 00000000 fd:01 1835037    /usr/lib/libc.so.6".
+func parseMemoryMapHeader(fields []string, m *MemoryMapsStat) error {
+	if len(fields) < 5 {
+		return fmt.Errorf("malformed smaps header: %s", strings.Join(fields, " "))
+	}
+	start, end, found := strings.Cut(fields[0], "-")
+	if !found {
+		return fmt.Errorf("malformed smaps address range: %s", fields[0])
+	}
+	var err error
+	if m.StartAddr, err = strconv.ParseUint(start, 16, 64); err != nil {
+		return err
+	}
+	if m.EndAddr, err = strconv.ParseUint(end, 16, 64); err != nil {
+		return err
+	}
+	if m.Offset, err = strconv.ParseUint(fields[2], 16, 64); err != nil {
+		return err
+	}
+	if m.Inode, err = strconv.ParseUint(fields[4], 10, 64); err != nil {
+		return err
+	}
+	m.Perms = fields[1]
+	m.Dev = fields[3]
+	return nil
+}
+
 func (p *Process) EnvironWithContext(ctx context.Context) ([]string, error) {
 	environPath := common.HostProcWithContext(ctx, strconv.Itoa(int(p.Pid)), "environ")
 
@@ -651,6 +1192,183 @@ func (p *Process) fillFromfdWithContext(ctx context.Context) (int32, []OpenFiles
 	return numFDs, openfiles, nil
 }
 
+func (p *Process) FileDescriptorsWithContext(ctx context.Context) ([]FileDescriptorStat, error) {
+	statPath, fnames, err := p.fillFromfdListWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	infoPath := common.HostProcWithContext(ctx, strconv.Itoa(int(p.Pid)), "fdinfo")
+
+	ret := make([]FileDescriptorStat, 0, len(fnames))
+	for _, name := range fnames {
+		fd, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		fdPath := filepath.Join(statPath, name)
+		link, err := common.Readlink(fdPath)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				// closed since the directory was read
+				continue
+			}
+			return nil, err
+		}
+		stat := FileDescriptorStat{Fd: fd, Path: link}
+		stat.Type, stat.AnonInodeType, stat.Inode = classifyFileDescriptor(fdPath, link)
+
+		contents, err := os.ReadFile(filepath.Join(infoPath, name))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		if err := parseFdinfo(contents, &stat); err != nil {
+			return nil, err
+		}
+		ret = append(ret, stat)
+	}
+	return ret, nil
+}
+
+// classifyFileDescriptor returns the type of the file descriptor fdPath from
+// the target of its link, the kind of an anon inode, and the inode number.
+func classifyFileDescriptor(fdPath, link string) (FileDescriptorType, string, uint64) {
+	if !strings.HasPrefix(link, "/") {
+		// socket:[12345], pipe:[12345], anon_inode:[eventfd],
+		// anon_inode:inotify, net:[4026531840]...
+		kind, rest, _ := strings.Cut(link, ":")
+		rest = strings.Trim(rest, "[]")
+		switch kind {
+		case "socket", "pipe":
+			inode, _ := strconv.ParseUint(rest, 10, 64)
+			return FileDescriptorType(kind), "", inode
+		case "anon_inode":
+			return FileDescriptorAnonInode, rest, 0
+		}
+		return FileDescriptorOther, "", 0
+	}
+
+	var st unix.Stat_t
+	if err := unix.Stat(fdPath, &st); err != nil {
+		if strings.HasPrefix(link, "/dev/") {
+			return FileDescriptorDevice, "", 0
+		}
+		return FileDescriptorFile, "", 0
+	}
+	switch st.Mode & unix.S_IFMT {
+	case unix.S_IFDIR:
+		return FileDescriptorDir, "", st.Ino
+	case unix.S_IFCHR, unix.S_IFBLK:
+		return FileDescriptorDevice, "", st.Ino
+	case unix.S_IFIFO:
+		return FileDescriptorPipe, "", st.Ino
+	case unix.S_IFSOCK:
+		return FileDescriptorSocket, "", st.Ino
+	}
+	return FileDescriptorFile, "", st.Ino
+}
+
+// parseFdinfo parses the common lines of /proc/(pid)/fdinfo/(fd), the other
+// lines depend on the type of the file.
+func parseFdinfo(contents []byte, stat *FileDescriptorStat) error {
+	for _, line := range strings.Split(string(contents), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "pos":
+			v, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return err
+			}
+			stat.Pos = v
+		case "flags":
+			v, err := strconv.ParseUint(value, 8, 32)
+			if err != nil {
+				return err
+			}
+			stat.Flags = uint32(v)
+		case "mnt_id":
+			v, err := strconv.ParseInt(value, 10, 32)
+			if err != nil {
+				return err
+			}
+			stat.MntID = int32(v)
+		case "ino":
+			// not printed by old kernels
+			v, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return err
+			}
+			stat.Inode = v
+		}
+	}
+	return nil
+}
+
+func (p *Process) NamespacesWithContext(ctx context.Context) (*NamespacesStat, error) {
+	pid := strconv.Itoa(int(p.Pid))
+	ret := &NamespacesStat{}
+	links := map[string]*uint64{
+		"cgroup":            &ret.Cgroup,
+		"ipc":               &ret.IPC,
+		"mnt":               &ret.Mnt,
+		"net":               &ret.Net,
+		"pid":               &ret.Pid,
+		"pid_for_children":  &ret.PidForChildren,
+		"time":              &ret.Time,
+		"time_for_children": &ret.TimeForChildren,
+		"user":              &ret.User,
+		"uts":               &ret.UTS,
+	}
+	for name, inode := range links {
+		link, err := os.Readlink(common.HostProcWithContext(ctx, pid, "ns", name))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				// not supported by the kernel
+				continue
+			}
+			return nil, err
+		}
+		if *inode, err = common.ParseNsLink(link); err != nil {
+			return nil, err
+		}
+	}
+
+	contents, err := os.ReadFile(common.HostProcWithContext(ctx, pid, "status"))
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(contents), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || (key != "NSpid" && key != "NStgid") {
+			continue
+		}
+		var pids []int32
+		for _, field := range strings.Fields(value) {
+			v, err := strconv.ParseInt(field, 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			pids = append(pids, int32(v))
+		}
+		if key == "NSpid" {
+			ret.NSpid = pids
+		} else {
+			ret.NStgid = pids
+		}
+	}
+	return ret, nil
+}
+
+func (p *Process) CgroupsWithContext(ctx context.Context) ([]cgroup.Membership, error) {
+	return cgroup.PidCgroupsWithContext(ctx, p.Pid)
+}
+
 // Get cwd from /proc/(pid)/cwd
 func (p *Process) fillFromCwdWithContext(ctx context.Context) (string, error) {
 	pid := p.Pid
@@ -839,6 +1557,12 @@ func (p *Process) fillFromStatusWithContext(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	return p.fillFromStatusContentsWithContext(ctx, contents, p.CmdlineSliceWithContext)
+}
+
+// fillFromStatusContentsWithContext parses the content of /proc/(pid)/status.
+// cmdlineSlice is only called to extend a name truncated by the kernel.
+func (p *Process) fillFromStatusContentsWithContext(ctx context.Context, contents []byte, cmdlineSlice func(context.Context) ([]string, error)) error {
 	lines := strings.Split(string(contents), "\n")
 	p.numCtxSwitches = &NumCtxSwitchesStat{}
 	p.memInfo = &MemoryInfoStat{}
@@ -853,12 +1577,12 @@ func (p *Process) fillFromStatusWithContext(ctx context.Context) error {
 		case "Name":
 			p.name = strings.Trim(value, " \t")
 			if len(p.name) >= 15 {
-				cmdlineSlice, err := p.CmdlineSliceWithContext(ctx)
+				cmdline, err := cmdlineSlice(ctx)
 				if err != nil {
 					return err
 				}
-				if len(cmdlineSlice) > 0 {
-					extendedName := filepath.Base(cmdlineSlice[0])
+				if len(cmdline) > 0 {
+					extendedName := filepath.Base(cmdline[0])
 					if strings.HasPrefix(extendedName, p.name) {
 						p.name = extendedName
 					}
@@ -1055,6 +1779,14 @@ func (p *Process) fillFromTIDStatWithContext(ctx context.Context, tid int32) (ui
 	if err != nil {
 		return 0, 0, nil, 0, 0, 0, nil, err
 	}
+	bootTime, _ := bootTimeWithContext(ctx, enableBootTimeCache)
+	return p.fillFromStatContents(contents, bootTime)
+}
+
+// fillFromStatContents parses the content of /proc/(pid)/stat or
+// /proc/(pid)/task/(tid)/stat, bootTime is used to compute the create time.
+func (p *Process) fillFromStatContents(contents []byte, bootTime uint64) (uint64, int32, *cpu.TimesStat, int64, uint32, int32, *PageFaultsStat, error) {
+	pid := p.Pid
 	// Indexing from one, as described in `man proc` about the file /proc/[pid]/stat
 	fields := splitProcStat(contents)
 	if len(fields) < 23 {
@@ -1100,7 +1832,6 @@ func (p *Process) fillFromTIDStatWithContext(ctx context.Context, tid int32) (ui
 		Iowait: iotime / float64(clockTicks),
 	}
 
-	bootTime, _ := common.BootTimeWithContext(ctx, enableBootTimeCache)
 	t, err := strconv.ParseUint(fields[22], 10, 64)
 	if err != nil {
 		return 0, 0, nil, 0, 0, 0, nil, err
@@ -5,14 +5,24 @@ package process
 
 import (
 	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"regexp"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+
+	"github.com/shirou/gopsutil/v4/cgroup"
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/internal/common"
 )
 
 func TestFillFromfdWithContext(t *testing.T) {
@@ -70,6 +80,53 @@ func TestFillFromfdWithContext(t *testing.T) {
 	}
 }
 
+func TestFileDescriptors(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux")
+	p := &Process{Pid: 1060}
+	fds, err := p.FileDescriptors()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []FileDescriptorStat{
+		{Fd: 0, Path: "/dev/null", Type: FileDescriptorDevice, Inode: 4, Flags: 0o100000, MntID: 25},
+		{Fd: 1, Path: "socket:[4321]", Type: FileDescriptorSocket, Inode: 4321, Flags: 0o2000002, MntID: 8},
+		{Fd: 2, Path: "pipe:[5678]", Type: FileDescriptorPipe, Inode: 5678, Flags: 0o1, MntID: 14},
+		{Fd: 3, Path: "anon_inode:[eventfd]", Type: FileDescriptorAnonInode, AnonInodeType: "eventfd", Inode: 1057, Flags: 0o2004002, MntID: 15},
+		{Fd: 4, Path: "anon_inode:[eventpoll]", Type: FileDescriptorAnonInode, AnonInodeType: "eventpoll", Inode: 1057, Flags: 0o2000002, MntID: 15},
+		{Fd: 5, Path: "/var/log/server.log", Type: FileDescriptorFile, Inode: 131075, Pos: 8192, Flags: 0o2102001, MntID: 29},
+	}, fds)
+}
+
+func TestFileDescriptorsSelf(t *testing.T) {
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer r.Close()
+	defer w.Close()
+	f, err := os.CreateTemp(t.TempDir(), "fd")
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.WriteString("gopsutil")
+	require.NoError(t, err)
+
+	p, err := NewProcess(int32(os.Getpid()))
+	require.NoError(t, err)
+	fds, err := p.FileDescriptors()
+	require.NoError(t, err)
+	byFd := make(map[uintptr]FileDescriptorStat, len(fds))
+	for _, fd := range fds {
+		byFd[uintptr(fd.Fd)] = fd
+	}
+
+	assert.Equal(t, FileDescriptorPipe, byFd[r.Fd()].Type)
+	assert.Equal(t, FileDescriptorPipe, byFd[w.Fd()].Type)
+	assert.Equal(t, byFd[r.Fd()].Inode, byFd[w.Fd()].Inode)
+
+	file := byFd[f.Fd()]
+	assert.Equal(t, FileDescriptorFile, file.Type)
+	assert.Equal(t, f.Name(), file.Path)
+	assert.Equal(t, int64(len("gopsutil")), file.Pos)
+	assert.Equal(t, uint32(os.O_RDWR), file.Flags&uint32(os.O_RDWR|os.O_WRONLY))
+	assert.NotZero(t, file.MntID)
+}
+
 func TestSplitProcStat(t *testing.T) {
 	expectedFieldsNum := 53
 	statLineContent := make([]string, expectedFieldsNum-1)
@@ -200,6 +257,505 @@ func TestFillFromStatusWithContext(t *testing.T) {
 	}
 }
 
+func TestProcessesInfoFromStatus(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux")
+	infos, err := ProcessesInfo([]Attr{AttrName, AttrStatus, AttrUids, AttrNumThreads})
+	require.NoError(t, err)
+
+	pids := make([]int32, 0, len(infos))
+	for _, info := range infos {
+		pids = append(pids, info.Pid)
+	}
+	// 68927 has no status file, as if it had exited
+	assert.Equal(t, []int32{1, 1060, 23819}, pids)
+
+	assert.Equal(t, "server", infos[1].Name)
+	assert.Equal(t, []string{Sleep}, infos[1].Status)
+	assert.Equal(t, []uint32{107, 107, 107, 107}, infos[1].Uids)
+	assert.Equal(t, int32(5), infos[1].NumThreads)
+	assert.Zero(t, infos[1].Ppid)
+	assert.Empty(t, infos[1].Gids)
+}
+
+func TestProcessesInfoReadsBootTimeOnce(t *testing.T) {
+	reads := 0
+	defer func(f func(context.Context, bool) (uint64, error)) { bootTimeWithContext = f }(bootTimeWithContext)
+	bootTimeWithContext = func(ctx context.Context, enableCache bool) (uint64, error) {
+		reads++
+		return common.BootTimeWithContext(ctx, enableCache)
+	}
+
+	infos, err := ProcessesInfo([]Attr{AttrCreateTime, AttrCPUTimes})
+	require.NoError(t, err)
+	require.Greater(t, len(infos), 1)
+	assert.Equal(t, 1, reads)
+	for _, info := range infos {
+		if info.CPUTimes != nil {
+			assert.Positive(t, info.CreateTime)
+		}
+	}
+}
+
+func TestCPUAffinity(t *testing.T) {
+	p, err := NewProcess(int32(os.Getpid()))
+	require.NoError(t, err)
+
+	cpus, err := p.CPUAffinity()
+	require.NoError(t, err)
+	require.NotEmpty(t, cpus)
+	// setting the current affinity does not need any privilege
+	require.NoError(t, p.SetCPUAffinity(cpus))
+	require.Error(t, p.SetCPUAffinity(nil))
+	require.Error(t, p.SetCPUAffinity([]int32{-1}))
+	require.Error(t, p.SetCPUAffinity([]int32{cpuSetSize}))
+
+	threads, err := p.Threads()
+	require.NoError(t, err)
+	for tid := range threads {
+		threadCPUs, err := p.ThreadCPUAffinity(tid)
+		if errors.Is(err, ErrorProcessNotRunning) {
+			// the thread exited
+			continue
+		}
+		require.NoError(t, err)
+		require.NotEmpty(t, threadCPUs)
+		err = p.SetThreadCPUAffinity(tid, threadCPUs)
+		if !errors.Is(err, ErrorProcessNotRunning) {
+			require.NoError(t, err)
+		}
+	}
+
+	_, err = p.ThreadCPUAffinity(-1)
+	require.ErrorIs(t, err, ErrorProcessNotRunning)
+}
+
+func TestSecurityContext(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux")
+	p := &Process{Pid: 23819}
+	sc, err := p.SecurityContext()
+	require.NoError(t, err)
+	assert.Equal(t, SecurityContextStat{
+		CapBnd:     0xa80425fb,
+		Seccomp:    SeccompFilter,
+		NoNewPrivs: true,
+		Label:      "docker-default (enforce)",
+	}, *sc)
+	assert.Equal(t, []string{
+		"CAP_CHOWN", "CAP_DAC_OVERRIDE", "CAP_FOWNER", "CAP_FSETID", "CAP_KILL",
+		"CAP_SETGID", "CAP_SETUID", "CAP_SETPCAP", "CAP_NET_BIND_SERVICE",
+		"CAP_NET_RAW", "CAP_SYS_CHROOT", "CAP_MKNOD", "CAP_AUDIT_WRITE", "CAP_SETFCAP",
+	}, sc.CapBnd.Names())
+	assert.True(t, sc.CapBnd.Has(unix.CAP_NET_RAW))
+	assert.False(t, sc.CapBnd.Has(unix.CAP_SYS_ADMIN))
+	assert.Empty(t, sc.CapEff.Names())
+
+	// no label without attr/current
+	p = &Process{Pid: 1}
+	sc, err = p.SecurityContext()
+	require.NoError(t, err)
+	assert.Equal(t, CapabilitySet(0x3fffffffff), sc.CapEff)
+	assert.Equal(t, "CAP_41,CAP_63", CapabilitySet(1<<41|1<<63).String())
+	assert.Equal(t, SeccompDisabled, sc.Seccomp)
+	assert.Empty(t, sc.Label)
+}
+
+func TestSecurityContextSelf(t *testing.T) {
+	p, err := NewProcess(int32(os.Getpid()))
+	require.NoError(t, err)
+	sc, err := p.SecurityContext()
+	require.NoError(t, err)
+	// the effective capabilities are always permitted
+	assert.Equal(t, sc.CapEff, sc.CapEff&sc.CapPrm)
+}
+
+func TestNamespaces(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux")
+	p := &Process{Pid: 23819}
+	ns, err := p.Namespaces()
+	require.NoError(t, err)
+	assert.Equal(t, NamespacesStat{
+		Cgroup:         4026532297,
+		IPC:            4026532295,
+		Mnt:            4026532293,
+		Net:            4026532298,
+		Pid:            4026532296,
+		PidForChildren: 4026532296,
+		User:           4026531837,
+		UTS:            4026532294,
+		NSpid:          []int32{23819, 1},
+		NStgid:         []int32{23819, 1},
+	}, *ns)
+
+	cgroups, err := p.Cgroups()
+	require.NoError(t, err)
+	assert.Equal(t, []cgroup.Membership{
+		{HierarchyID: 12, Controllers: []string{"memory"}, Path: "/docker/3f2a9c1b"},
+		{HierarchyID: 4, Controllers: []string{"cpu", "cpuacct"}, Path: "/docker/3f2a9c1b"},
+		{HierarchyID: 1, Controllers: []string{"name=systemd"}, Path: "/docker/3f2a9c1b"},
+		{HierarchyID: 0, Controllers: []string{}, Path: "/system.slice/docker-3f2a9c1b.scope"},
+	}, cgroups)
+}
+
+func TestNamespacesSelf(t *testing.T) {
+	p, err := NewProcess(int32(os.Getpid()))
+	require.NoError(t, err)
+	ns, err := p.Namespaces()
+	require.NoError(t, err)
+	link, err := os.Readlink("/proc/self/ns/net")
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("net:[%d]", ns.Net), link)
+	if len(ns.NSpid) > 0 {
+		assert.Equal(t, p.Pid, ns.NSpid[0])
+	}
+
+	cgroups, err := p.Cgroups()
+	require.NoError(t, err)
+	assert.NotEmpty(t, cgroups)
+}
+
+func TestFilterMatch(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux")
+	server := &Process{Pid: 1060}
+	container := &Process{Pid: 23819}
+
+	cases := []struct {
+		name      string
+		filter    Filter
+		server    bool
+		container bool
+	}{
+		{"any", Filter{}, true, true},
+		{"name", Filter{Name: "serv*"}, true, false},
+		{"status", Filter{Status: []string{Sleep}}, true, true},
+		{"other status", Filter{Status: []string{Running, Zombie}}, false, false},
+		{"uids", Filter{Uids: []uint32{107}}, true, false},
+		{"name and uids", Filter{Name: "serv*", Uids: []uint32{0}}, false, false},
+		{"cgroup", Filter{Cgroup: "/docker"}, false, true},
+		{"cgroup v2", Filter{Cgroup: "/system.slice/docker-3f2a9c1b.scope"}, false, true},
+		{"cgroup prefix", Filter{Cgroup: "/dock"}, false, false},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.server, tt.filter.Match(server))
+			assert.Equal(t, tt.container, tt.filter.Match(container))
+		})
+	}
+}
+
+func TestQueryCmdline(t *testing.T) {
+	cmd := exec.Command("sleep", "3.5")
+	require.NoError(t, cmd.Start())
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	procs, err := Query(Filter{
+		Name:    "sleep",
+		Cmdline: regexp.MustCompile(`^sleep 3\.5$`),
+		Ppids:   []int32{int32(os.Getpid())},
+	})
+	require.NoError(t, err)
+	require.Len(t, procs, 1)
+	assert.Equal(t, int32(cmd.Process.Pid), procs[0].Pid)
+}
+
+func TestIOPriority(t *testing.T) {
+	cmd := exec.Command("sleep", "3")
+	require.NoError(t, cmd.Start())
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	p, err := NewProcess(int32(cmd.Process.Pid))
+	require.NoError(t, err)
+
+	_, err = p.IOPriority()
+	require.NoError(t, err)
+
+	// lowering the priority does not need any privilege
+	require.NoError(t, p.SetIOnice(IOPriorityClassBE, 7))
+	prio, err := p.IOPriority()
+	require.NoError(t, err)
+	assert.Equal(t, IOPriorityStat{Class: IOPriorityClassBE, Level: 7}, *prio)
+	ioprio, err := p.IOnice()
+	require.NoError(t, err)
+	assert.Equal(t, int32(2<<13|7), ioprio)
+
+	require.NoError(t, p.SetIOnice(IOPriorityClassIdle, 0))
+	prio, err = p.IOPriority()
+	require.NoError(t, err)
+	assert.Equal(t, IOPriorityClassIdle, prio.Class)
+	assert.Equal(t, "idle", prio.Class.String())
+
+	require.Error(t, p.SetIOnice(IOPriorityClassBE, 8))
+	require.Error(t, p.SetIOnice(IOPriorityClassIdle, 1))
+	require.Error(t, p.SetIOnice(IOPriorityClass(4), 0))
+}
+
+func TestScheduler(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux")
+	p := &Process{Pid: 68927}
+	sched, err := p.Scheduler()
+	require.NoError(t, err)
+	assert.Equal(t, SchedulerStat{
+		Policy:     SchedOther,
+		RunTime:    123456789,
+		WaitTime:   4567890,
+		Timeslices: 42,
+	}, *sched)
+	assert.Equal(t, "other", sched.Policy.String())
+	assert.Equal(t, "rr", SchedRR.String())
+}
+
+func TestSchedulerSelf(t *testing.T) {
+	cmd := exec.Command("sleep", "3")
+	require.NoError(t, cmd.Start())
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	p, err := NewProcess(int32(cmd.Process.Pid))
+	require.NoError(t, err)
+	sched, err := p.Scheduler()
+	require.NoError(t, err)
+	assert.Equal(t, SchedOther, sched.Policy)
+	assert.Zero(t, sched.RTPriority)
+
+	// switching to SCHED_BATCH does not need any privilege
+	require.NoError(t, unix.SchedSetAttr(cmd.Process.Pid, &unix.SchedAttr{Policy: uint32(SchedBatch)}, 0))
+	sched, err = p.Scheduler()
+	require.NoError(t, err)
+	assert.Equal(t, SchedBatch, sched.Policy)
+
+	self, err := NewProcess(int32(os.Getpid()))
+	require.NoError(t, err)
+	sched, err = self.Scheduler()
+	require.NoError(t, err)
+	if _, err := os.Stat("/proc/self/schedstat"); err == nil {
+		assert.NotZero(t, sched.RunTime)
+		assert.NotZero(t, sched.Timeslices)
+	}
+}
+
+func TestSetters(t *testing.T) {
+	cmd := exec.Command("sleep", "3")
+	require.NoError(t, cmd.Start())
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	p, err := NewProcess(int32(cmd.Process.Pid))
+	require.NoError(t, err)
+
+	// lowering the priority does not need any privilege
+	require.NoError(t, p.SetNice(10))
+	nice, err := p.Nice()
+	require.NoError(t, err)
+	assert.Equal(t, int32(10), nice)
+	if os.Geteuid() != 0 {
+		require.ErrorIs(t, p.SetNice(0), os.ErrPermission)
+	}
+
+	limits, err := p.Rlimit()
+	require.NoError(t, err)
+	var nofile RlimitStat
+	for _, l := range limits {
+		if l.Resource == RLIMIT_NOFILE {
+			nofile = l
+		}
+	}
+	old, err := p.SetRlimit(RLIMIT_NOFILE, 64, nofile.Hard)
+	require.NoError(t, err)
+	assert.Equal(t, nofile, *old)
+	limits, err = p.Rlimit()
+	require.NoError(t, err)
+	for _, l := range limits {
+		if l.Resource == RLIMIT_NOFILE {
+			assert.Equal(t, uint64(64), l.Soft)
+		}
+	}
+	_, err = p.SetRlimit(RLIMIT_NOFILE, 2, 1)
+	require.Error(t, err)
+
+	require.NoError(t, p.SetOOMScoreAdj(500))
+	adj, err := os.ReadFile(fmt.Sprintf("/proc/%d/oom_score_adj", p.Pid))
+	require.NoError(t, err)
+	assert.Equal(t, "500", strings.TrimSpace(string(adj)))
+	require.Error(t, p.SetOOMScoreAdj(1001))
+
+	cmd.Process.Kill()
+	cmd.Wait()
+	require.ErrorIs(t, p.SetNice(10), ErrorProcessNotRunning)
+}
+
+func TestPidfd(t *testing.T) {
+	cmd := exec.Command("sleep", "10")
+	require.NoError(t, cmd.Start())
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	p, err := NewProcess(int32(cmd.Process.Pid))
+	require.NoError(t, err)
+	require.NoError(t, p.OpenPidfd())
+	defer p.ClosePidfd()
+
+	require.NoError(t, p.SendSignal(0))
+	require.NoError(t, p.Terminate())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, p.WaitWithContext(ctx))
+
+	// the pid is free once reaped, the pidfd still refers to the old process
+	cmd.Wait()
+	require.ErrorIs(t, p.Kill(), ErrorProcessNotRunning)
+	require.NoError(t, p.WaitWithContext(ctx))
+	require.NoError(t, p.ClosePidfd())
+}
+
+func TestPidfdReused(t *testing.T) {
+	p, err := NewProcess(int32(os.Getpid()))
+	require.NoError(t, err)
+	// as if the pid had been reused by another process
+	p.createTime--
+	require.ErrorIs(t, p.OpenPidfd(), ErrorProcessNotRunning)
+}
+
+func TestWaitPoll(t *testing.T) {
+	cmd := exec.Command("sleep", "10")
+	require.NoError(t, cmd.Start())
+	defer cmd.Wait()
+
+	p, err := NewProcess(int32(cmd.Process.Pid))
+	require.NoError(t, err)
+	require.NoError(t, cmd.Process.Kill())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// the process is a zombie until reaped
+	require.NoError(t, p.waitPollWithContext(ctx))
+}
+
+func TestWatcherProcConnector(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	w, err := NewWatcherWithContext(ctx, WatchOptions{Exec: true})
+	require.NoError(t, err)
+	defer w.Close()
+	if !w.ProcConnector() {
+		t.Skip("proc connector not available")
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", "exec sleep 0.1")
+	require.NoError(t, cmd.Start())
+	pid := int32(cmd.Process.Pid)
+	go cmd.Wait()
+
+	var events []Event
+	for e := range w.Events() {
+		if e.Pid != pid {
+			continue
+		}
+		events = append(events, e)
+		if e.Type == EventExit {
+			break
+		}
+	}
+	require.NoError(t, ctx.Err())
+	require.NotEmpty(t, events)
+	assert.Equal(t, EventStart, events[0].Type)
+	assert.Equal(t, int32(os.Getpid()), events[0].Ppid)
+	last := events[len(events)-1]
+	assert.Equal(t, EventExit, last.Type)
+	assert.Equal(t, "sleep", last.Name)
+	assert.Equal(t, events[0].CreateTime, last.CreateTime)
+}
+
+func TestWatcherProcEvent(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux")
+	w := &Watcher{
+		opts:  WatchOptions{Comm: true},
+		known: map[int32]*TreeNode{1: {Pid: 1, Name: "init"}},
+	}
+	defer func(f func(context.Context, bool) (uint64, error)) { bootTimeWithContext = f }(bootTimeWithContext)
+	bootTimeWithContext = func(context.Context, bool) (uint64, error) {
+		t.Error("the boot time must be read when the watcher starts, not per event")
+		return 0, nil
+	}
+	u32s := func(values ...uint32) []byte {
+		b := make([]byte, 0, len(values)*4)
+		for _, v := range values {
+			b = binary.NativeEndian.AppendUint32(b, v)
+		}
+		return b
+	}
+	ctx := context.Background()
+
+	// a thread
+	_, ok := w.procEvent(ctx, procEventFork, u32s(1, 1, 101, 100))
+	assert.False(t, ok)
+
+	e, ok := w.procEvent(ctx, procEventFork, u32s(1, 1, 100, 100))
+	require.True(t, ok)
+	assert.Equal(t, Event{Type: EventStart, Pid: 100, Ppid: 1, Name: "init"}, e)
+
+	comm := append(u32s(100, 100), []byte("worker\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00")...)
+	e, ok = w.procEvent(ctx, procEventComm, comm)
+	require.True(t, ok)
+	assert.Equal(t, Event{Type: EventComm, Pid: 100, Ppid: 1, Name: "worker"}, e)
+
+	e, ok = w.procEvent(ctx, procEventExit, u32s(100, 100, 0, 17, 1, 1))
+	require.True(t, ok)
+	assert.Equal(t, Event{Type: EventExit, Pid: 100, Ppid: 1, Name: "worker"}, e)
+	assert.NotContains(t, w.known, int32(100))
+
+	// not known
+	_, ok = w.procEvent(ctx, procEventExit, u32s(200, 200, 0, 17, 1, 1))
+	assert.False(t, ok)
+}
+
+func TestThreadsInfo(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux")
+	p := &Process{Pid: 1060}
+	threads, err := p.ThreadsInfo()
+	require.NoError(t, err)
+	require.Len(t, threads, 2)
+
+	assert.Equal(t, ThreadInfoStat{
+		Tid:            1060,
+		Name:           "server",
+		Status:         Sleep,
+		CPU:            2,
+		CPUTimes:       &cpu.TimesStat{CPU: "cpu", User: 350 / float64(clockTicks), System: 120 / float64(clockTicks), Iowait: 4 / float64(clockTicks)},
+		NumCtxSwitches: &NumCtxSwitchesStat{Voluntary: 3, Involuntary: 146},
+		PageFaults:     &PageFaultsStat{MinorFaults: 2150, MajorFaults: 12},
+		Wchan:          "futex_wait_queue",
+	}, threads[0])
+	assert.Equal(t, int32(1063), threads[1].Tid)
+	assert.Equal(t, "server worker", threads[1].Name)
+	assert.Equal(t, Running, threads[1].Status)
+	assert.Equal(t, int32(5), threads[1].CPU)
+	assert.Equal(t, int64(9120), threads[1].NumCtxSwitches.Involuntary)
+	assert.Empty(t, threads[1].Wchan)
+}
+
+func TestThreadsInfoSelf(t *testing.T) {
+	p, err := NewProcess(int32(os.Getpid()))
+	require.NoError(t, err)
+	threads, err := p.ThreadsInfo()
+	require.NoError(t, err)
+	require.NotEmpty(t, threads)
+	assert.Equal(t, p.Pid, threads[0].Tid)
+	for _, thread := range threads {
+		assert.NotEmpty(t, thread.Name)
+		assert.NotNil(t, thread.NumCtxSwitches)
+	}
+}
+
 func Benchmark_fillFromCommWithContext(b *testing.B) {
 	b.Setenv("HOST_PROC", "testdata/linux")
 	pid := 1060
@@ -247,58 +803,163 @@ func TestProcessMemoryMaps(t *testing.T) {
 
 	expected := &[]MemoryMapsStat{
 		{
-			"[vvar]",
-			0,
-			1,
-			0,
-			3,
-			4,
-			5,
-			6,
-			7,
-			8,
-			9,
+			Path:         "[vvar]",
+			Size:         1,
+			SharedClean:  3,
+			SharedDirty:  4,
+			PrivateClean: 5,
+			PrivateDirty: 6,
+			Referenced:   7,
+			Anonymous:    8,
+			Swap:         9,
+			StartAddr:    0xffffb5ecc000,
+			EndAddr:      0xffffb5ece000,
+			Perms:        "r--p",
+			Dev:          "00:00",
 		},
 		{
-			"",
-			0,
-			1,
-			2,
-			3,
-			4,
-			0,
-			6,
-			7,
-			8,
-			9,
+			Size:         1,
+			Pss:          2,
+			SharedClean:  3,
+			SharedDirty:  4,
+			PrivateDirty: 6,
+			Referenced:   7,
+			Anonymous:    8,
+			Swap:         9,
+			StartAddr:    0xffffb5eca000,
+			EndAddr:      0xffffb5ecc000,
+			Perms:        "rw-p",
+			Dev:          "00:00",
+			LazyFree:     13,
 		},
 		{
-			"[vdso]",
-			0,
-			1,
-			2,
-			3,
-			4,
-			5,
-			0,
-			7,
-			8,
-			9,
+			Path:         "[vdso]",
+			Size:         1,
+			Pss:          2,
+			SharedClean:  3,
+			SharedDirty:  4,
+			PrivateClean: 5,
+			Referenced:   7,
+			Anonymous:    8,
+			Swap:         9,
+			StartAddr:    0xffffb5ece000,
+			EndAddr:      0xffffb5ecf000,
+			Perms:        "r-xp",
+			Dev:          "00:00",
 		},
 		{
-			"/usr/lib/aarch64-linux-gnu/ld-linux-aarch64.so.1",
-			0,
-			1,
-			2,
-			3,
-			4,
-			5,
-			6,
-			7,
-			0,
-			9,
+			Path:          "/usr/lib/aarch64-linux-gnu/ld-linux-aarch64.so.1",
+			Size:          1,
+			Pss:           2,
+			SharedClean:   3,
+			SharedDirty:   4,
+			PrivateClean:  5,
+			PrivateDirty:  6,
+			Referenced:    7,
+			Swap:          9,
+			StartAddr:     0xffffb5ecf000,
+			EndAddr:       0xffffb5ed1000,
+			Perms:         "r--p",
+			Offset:        0x2a000,
+			Dev:           "00:3d",
+			Inode:         2238525,
+			AnonHugePages: 10,
+			Locked:        11,
+			SwapPss:       12,
 		},
 	}
 
 	require.Equal(t, expected, maps)
 }
+
+func TestProcessMemoryMapsGrouped(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux")
+	p := &Process{Pid: 1}
+	maps, err := p.MemoryMaps(true)
+	require.NoError(t, err)
+
+	// read from smaps_rollup, not summed from smaps
+	require.Equal(t, &[]MemoryMapsStat{
+		{
+			Rss:           1300,
+			Pss:           451,
+			SharedClean:   1160,
+			PrivateClean:  40,
+			PrivateDirty:  100,
+			Referenced:    1300,
+			Anonymous:     100,
+			Swap:          24,
+			LazyFree:      4,
+			AnonHugePages: 2048,
+			Locked:        8,
+			SwapPss:       12,
+		},
+	}, maps)
+}
+
+func TestMemoryFullInfo(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux")
+	p := &Process{Pid: 1}
+	mem, err := p.MemoryFullInfo()
+	require.NoError(t, err)
+	assert.Equal(t, MemoryFullInfoStat{
+		RSS:  287 * pageSize,
+		VMS:  2703 * pageSize,
+		USS:  140 * 1024,
+		PSS:  451 * 1024,
+		Swap: 24 * 1024,
+	}, *mem)
+}
+
+func TestMemoryFullInfoSelf(t *testing.T) {
+	p, err := NewProcess(int32(os.Getpid()))
+	require.NoError(t, err)
+	mem, err := p.MemoryFullInfo()
+	require.NoError(t, err)
+	assert.NotZero(t, mem.USS)
+	// both read from the same smaps_rollup
+	assert.LessOrEqual(t, mem.USS, mem.PSS)
+}
+
+func TestMemoryInfoStatus(t *testing.T) {
+	t.Setenv("HOST_PROC", "testdata/linux")
+	p := &Process{Pid: 1060}
+	mem, err := p.MemoryInfoStatus()
+	require.NoError(t, err)
+	assert.Equal(t, MemoryInfoStatusStat{
+		VMPeak:  664744 * 1024,
+		VMS:     664744 * 1024,
+		HWM:     2892 * 1024,
+		RSS:     2892 * 1024,
+		RSSAnon: 524 * 1024,
+		RSSFile: 2368 * 1024,
+		Data:    5932 * 1024,
+		Stack:   132 * 1024,
+		Text:    1304 * 1024,
+		Lib:     1180 * 1024,
+		PTE:     44 * 1024,
+	}, *mem)
+
+	// kernel threads have no memory lines
+	p = &Process{Pid: 1}
+	mem, err = p.MemoryInfoStatus()
+	require.NoError(t, err)
+	assert.Equal(t, MemoryInfoStatusStat{}, *mem)
+}
+
+func TestParseTreeNodeStat(t *testing.T) {
+	contents := []byte("4321 (my (weird) name) S 1200 4321 1200 0 -1 4194304 1500 0 3 0 250 75 0 0 20 0 4 0 5000 104857600 2560 18446744073709551615 1 1 0 0 0 0 0 0 0 0 0 0 17 3 0 0 0 0 0\n")
+	n, err := parseTreeNodeStat(contents, 1700000000)
+	require.NoError(t, err)
+	assert.Equal(t, int32(4321), n.Pid)
+	assert.Equal(t, int32(1200), n.Ppid)
+	assert.Equal(t, "my (weird) name", n.Name)
+	assert.InDelta(t, 250/float64(clockTicks), n.CPUTimes.User, 0.0001)
+	assert.InDelta(t, 75/float64(clockTicks), n.CPUTimes.System, 0.0001)
+	assert.Equal(t, int64(1700000000)*1000+int64(5000)*1000/int64(clockTicks), n.CreateTime)
+	assert.Equal(t, uint64(104857600), n.VMS)
+	assert.Equal(t, 2560*pageSize, n.RSS)
+
+	_, err = parseTreeNodeStat([]byte("4321 (short) S 1"), 0)
+	require.Error(t, err)
+}
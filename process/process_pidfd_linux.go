@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//go:build linux
+
+package process
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+func (p *Process) OpenPidfdWithContext(ctx context.Context) error {
+	p.pidfdMutex.Lock()
+	defer p.pidfdMutex.Unlock()
+	if p.pidfdOpen {
+		return nil
+	}
+	fd, err := p.openPidfdWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	p.pidfd = fd
+	p.pidfdOpen = true
+	return nil
+}
+
+// ClosePidfd releases the pidfd opened by OpenPidfd.
+func (p *Process) ClosePidfd() error {
+	p.pidfdMutex.Lock()
+	defer p.pidfdMutex.Unlock()
+	if !p.pidfdOpen {
+		return nil
+	}
+	p.pidfdOpen = false
+	if p.pidfd < 0 {
+		return nil
+	}
+	return unix.Close(p.pidfd)
+}
+
+// openPidfdWithContext opens a pidfd and checks that it refers to the
+// process of the create time known by p. It returns -1 if the kernel does
+// not support pidfd.
+func (p *Process) openPidfdWithContext(ctx context.Context) (int, error) {
+	createTime, err := p.CreateTimeWithContext(ctx)
+	if err != nil {
+		if isProcessGone(err) {
+			return -1, ErrorProcessNotRunning
+		}
+		return -1, err
+	}
+	fd, err := unix.PidfdOpen(int(p.Pid), 0)
+	if err != nil {
+		switch {
+		case errors.Is(err, unix.ENOSYS):
+			return -1, nil
+		case errors.Is(err, unix.ESRCH):
+			return -1, ErrorProcessNotRunning
+		}
+		return -1, err
+	}
+	// the pid may have been reused before the pidfd was opened
+	current, err := p.createTimeWithContext(ctx)
+	if err != nil || current != createTime {
+		unix.Close(fd)
+		if err != nil && !isProcessGone(err) {
+			return -1, err
+		}
+		return -1, ErrorProcessNotRunning
+	}
+	return fd, nil
+}
+
+// sendSignalPidfd sends sig with the pidfd opened by OpenPidfd. It returns
+// false if no pidfd is open, or if the kernel does not support pidfd and the
+// process is still the same, to let the caller send sig to the pid.
+func (p *Process) sendSignalPidfd(ctx context.Context, sig Signal) (bool, error) {
+	p.pidfdMutex.Lock()
+	defer p.pidfdMutex.Unlock()
+	if !p.pidfdOpen {
+		return false, nil
+	}
+	if p.pidfd < 0 {
+		running, err := p.IsRunningWithContext(ctx)
+		if err != nil && !isProcessGone(err) {
+			return false, err
+		}
+		if !running {
+			return false, ErrorProcessNotRunning
+		}
+		return false, nil
+	}
+	if err := unix.PidfdSendSignal(p.pidfd, sig, nil, 0); err != nil {
+		if errors.Is(err, unix.ESRCH) {
+			return true, ErrorProcessNotRunning
+		}
+		return true, err
+	}
+	return true, nil
+}
+
+func (p *Process) WaitWithContext(ctx context.Context) error {
+	fd, err := p.waitPidfdWithContext(ctx)
+	if err != nil {
+		if errors.Is(err, ErrorProcessNotRunning) {
+			return nil
+		}
+		return err
+	}
+	if fd < 0 {
+		return p.waitPollWithContext(ctx)
+	}
+	defer unix.Close(fd)
+
+	fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+	for {
+		// wake up regularly to check ctx
+		n, err := unix.Poll(fds, int(waitPollInterval.Milliseconds()))
+		if err != nil && !errors.Is(err, unix.EINTR) {
+			return err
+		}
+		if n > 0 {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// waitPidfdWithContext returns a pidfd owned by the caller, duplicated from
+// the one opened by OpenPidfd if any, or -1 if the kernel does not support
+// pidfd.
+func (p *Process) waitPidfdWithContext(ctx context.Context) (int, error) {
+	p.pidfdMutex.Lock()
+	defer p.pidfdMutex.Unlock()
+	if !p.pidfdOpen {
+		return p.openPidfdWithContext(ctx)
+	}
+	if p.pidfd < 0 {
+		return -1, nil
+	}
+	return unix.FcntlInt(uintptr(p.pidfd), unix.F_DUPFD_CLOEXEC, 0)
+}
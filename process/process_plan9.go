@@ -7,6 +7,7 @@ import (
 	"context"
 	"syscall"
 
+	"github.com/shirou/gopsutil/v4/cgroup"
 	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/shirou/gopsutil/v4/internal/common"
 	"github.com/shirou/gopsutil/v4/net"
@@ -30,6 +31,8 @@ type MemoryMapsStat struct {
 
 type MemoryInfoExStat struct{}
 
+type MemoryInfoStatusStat struct{}
+
 func pidsWithContext(_ context.Context) ([]int32, error) {
 	return nil, common.ErrNotImplementedError
 }
@@ -94,6 +97,10 @@ func (*Process) GroupsWithContext(_ context.Context) ([]uint32, error) {
 	return nil, common.ErrNotImplementedError
 }
 
+func (*Process) SecurityContextWithContext(_ context.Context) (*SecurityContextStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
 func (*Process) TerminalWithContext(_ context.Context) (string, error) {
 	return "", common.ErrNotImplementedError
 }
@@ -106,6 +113,18 @@ func (*Process) IOniceWithContext(_ context.Context) (int32, error) {
 	return 0, common.ErrNotImplementedError
 }
 
+func (*Process) SchedulerWithContext(_ context.Context) (*SchedulerStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*Process) IOPriorityWithContext(_ context.Context) (*IOPriorityStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*Process) SetIOniceWithContext(_ context.Context, _ IOPriorityClass, _ int32) error {
+	return common.ErrNotImplementedError
+}
+
 func (*Process) RlimitWithContext(_ context.Context) ([]RlimitStat, error) {
 	return nil, common.ErrNotImplementedError
 }
@@ -114,6 +133,31 @@ func (*Process) RlimitUsageWithContext(_ context.Context, _ bool) ([]RlimitStat,
 	return nil, common.ErrNotImplementedError
 }
 
+func (*Process) SetRlimitWithContext(_ context.Context, _ int32, _, _ uint64) (*RlimitStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*Process) SetNiceWithContext(_ context.Context, _ int32) error {
+	return common.ErrNotImplementedError
+}
+
+func (*Process) SetOOMScoreAdjWithContext(_ context.Context, _ int32) error {
+	return common.ErrNotImplementedError
+}
+
+func (*Process) OpenPidfdWithContext(_ context.Context) error {
+	return common.ErrNotImplementedError
+}
+
+// ClosePidfd releases the pidfd opened by OpenPidfd.
+func (*Process) ClosePidfd() error {
+	return common.ErrNotImplementedError
+}
+
+func (p *Process) WaitWithContext(ctx context.Context) error {
+	return p.waitPollWithContext(ctx)
+}
+
 func (*Process) IOCountersWithContext(_ context.Context) (*IOCountersStat, error) {
 	return nil, common.ErrNotImplementedError
 }
@@ -138,18 +182,42 @@ func (*Process) TimesWithContext(_ context.Context) (*cpu.TimesStat, error) {
 	return nil, common.ErrNotImplementedError
 }
 
+func (*Process) ThreadsInfoWithContext(_ context.Context) ([]ThreadInfoStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
 func (*Process) CPUAffinityWithContext(_ context.Context) ([]int32, error) {
 	return nil, common.ErrNotImplementedError
 }
 
+func (*Process) SetCPUAffinityWithContext(_ context.Context, _ []int32) error {
+	return common.ErrNotImplementedError
+}
+
+func (*Process) ThreadCPUAffinityWithContext(_ context.Context, _ int32) ([]int32, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*Process) SetThreadCPUAffinityWithContext(_ context.Context, _ int32, _ []int32) error {
+	return common.ErrNotImplementedError
+}
+
 func (*Process) MemoryInfoWithContext(_ context.Context) (*MemoryInfoStat, error) {
 	return nil, common.ErrNotImplementedError
 }
 
+func (*Process) MemoryFullInfoWithContext(_ context.Context) (*MemoryFullInfoStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
 func (*Process) MemoryInfoExWithContext(_ context.Context) (*MemoryInfoExStat, error) {
 	return nil, common.ErrNotImplementedError
 }
 
+func (*Process) MemoryInfoStatusWithContext(_ context.Context) (*MemoryInfoStatusStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
 func (*Process) PageFaultsWithContext(_ context.Context) (*PageFaultsStat, error) {
 	return nil, common.ErrNotImplementedError
 }
@@ -162,6 +230,18 @@ func (*Process) OpenFilesWithContext(_ context.Context) ([]OpenFilesStat, error)
 	return nil, common.ErrNotImplementedError
 }
 
+func (*Process) FileDescriptorsWithContext(_ context.Context) ([]FileDescriptorStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*Process) NamespacesWithContext(_ context.Context) (*NamespacesStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*Process) CgroupsWithContext(_ context.Context) ([]cgroup.Membership, error) {
+	return nil, common.ErrNotImplementedError
+}
+
 func (*Process) ConnectionsWithContext(_ context.Context) ([]net.ConnectionStat, error) {
 	return nil, common.ErrNotImplementedError
 }
@@ -140,7 +140,11 @@ func PidExistsWithContext(ctx context.Context, pid int32) (bool, error) {
 	return false, err
 }
 
-func (p *Process) SendSignalWithContext(_ context.Context, sig syscall.Signal) error {
+func (p *Process) SendSignalWithContext(ctx context.Context, sig syscall.Signal) error {
+	if sent, err := p.sendSignalPidfd(ctx, sig); sent || err != nil {
+		return err
+	}
+
 	process, err := os.FindProcess(int(p.Pid))
 	if err != nil {
 		return err
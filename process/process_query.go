@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: BSD-3-Clause
+package process
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/cgroup"
+)
+
+// Filter selects processes, like pgrep. A process matches when it matches
+// every set field, the zero value of a field matches any process. The fields
+// are checked from the cheapest to read to the most expensive one, and the
+// checks stop at the first mismatch.
+type Filter struct {
+	// Name and Exe are shell patterns, as of path.Match, matched against
+	// the name and the path of the executable of the process, such as
+	// "java" or "/usr/lib/jvm/*/bin/java".
+	Name string
+	Exe  string
+	// Cmdline matches the command line, with the arguments separated by a
+	// space, such as regexp.MustCompile(`--port[ =]`).
+	Cmdline *regexp.Regexp
+	// Usernames and Uids match the processes of any of the users, by name
+	// or by real uid.
+	Usernames []string
+	Uids      []uint32
+	// Ppids matches the children of any of the processes.
+	Ppids []int32
+	// Status matches the processes in any of the states, such as Running or
+	// Sleep.
+	Status []string
+	// Cgroup matches the processes in the cgroup at this path, such as
+	// "/system.slice/nginx.service", or in any cgroup below it. This is
+	// only implemented on Linux.
+	Cgroup string
+}
+
+// Query returns the processes matching f.
+func Query(f Filter) ([]*Process, error) {
+	return QueryWithContext(context.Background(), f)
+}
+
+func QueryWithContext(ctx context.Context, f Filter) ([]*Process, error) {
+	for _, pattern := range []string{f.Name, f.Exe} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, err
+		}
+	}
+	procs, err := ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Process, 0)
+	for _, p := range procs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if f.MatchWithContext(ctx, p) {
+			ret = append(ret, p)
+		}
+	}
+	return ret, nil
+}
+
+// Match returns true if p matches f. A field which cannot be read, because
+// the process exited or is owned by another user, does not match.
+func (f Filter) Match(p *Process) bool {
+	return f.MatchWithContext(context.Background(), p)
+}
+
+func (f Filter) MatchWithContext(ctx context.Context, p *Process) bool {
+	if f.Name != "" {
+		name, err := p.NameWithContext(ctx)
+		if err != nil || !matchPattern(f.Name, name) {
+			return false
+		}
+	}
+	if len(f.Ppids) > 0 {
+		ppid, err := p.PpidWithContext(ctx)
+		if err != nil || !slices.Contains(f.Ppids, ppid) {
+			return false
+		}
+	}
+	if len(f.Status) > 0 {
+		status, err := p.StatusWithContext(ctx)
+		if err != nil || !slices.ContainsFunc(status, func(s string) bool { return slices.Contains(f.Status, s) }) {
+			return false
+		}
+	}
+	if len(f.Uids) > 0 {
+		uids, err := p.UidsWithContext(ctx)
+		if err != nil || len(uids) == 0 || !slices.Contains(f.Uids, uids[0]) {
+			return false
+		}
+	}
+	if f.Exe != "" {
+		exe, err := p.ExeWithContext(ctx)
+		if err != nil || !matchPattern(f.Exe, exe) {
+			return false
+		}
+	}
+	if f.Cmdline != nil {
+		cmdline, err := p.CmdlineWithContext(ctx)
+		if err != nil || !f.Cmdline.MatchString(cmdline) {
+			return false
+		}
+	}
+	if len(f.Usernames) > 0 {
+		username, err := p.UsernameWithContext(ctx)
+		if err != nil || !slices.Contains(f.Usernames, username) {
+			return false
+		}
+	}
+	if f.Cgroup != "" {
+		cgroups, err := p.CgroupsWithContext(ctx)
+		if err != nil {
+			return false
+		}
+		prefix := strings.TrimSuffix(f.Cgroup, "/") + "/"
+		return slices.ContainsFunc(cgroups, func(c cgroup.Membership) bool {
+			return c.Path == f.Cgroup || strings.HasPrefix(c.Path, prefix)
+		})
+	}
+	return true
+}
+
+// matchPattern returns false for a malformed pattern, which Query rejects.
+func matchPattern(pattern, name string) bool {
+	ok, err := path.Match(pattern, name)
+	return err == nil && ok
+}
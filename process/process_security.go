@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: BSD-3-Clause
+package process
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CapabilitySet is a set of Linux capabilities, bit n is set when the set
+// holds the capability numbered n in linux/capability.h.
+type CapabilitySet uint64
+
+// capabilityNames are the names of the capabilities, by number.
+var capabilityNames = []string{
+	"CAP_CHOWN",
+	"CAP_DAC_OVERRIDE",
+	"CAP_DAC_READ_SEARCH",
+	"CAP_FOWNER",
+	"CAP_FSETID",
+	"CAP_KILL",
+	"CAP_SETGID",
+	"CAP_SETUID",
+	"CAP_SETPCAP",
+	"CAP_LINUX_IMMUTABLE",
+	"CAP_NET_BIND_SERVICE",
+	"CAP_NET_BROADCAST",
+	"CAP_NET_ADMIN",
+	"CAP_NET_RAW",
+	"CAP_IPC_LOCK",
+	"CAP_IPC_OWNER",
+	"CAP_SYS_MODULE",
+	"CAP_SYS_RAWIO",
+	"CAP_SYS_CHROOT",
+	"CAP_SYS_PTRACE",
+	"CAP_SYS_PACCT",
+	"CAP_SYS_ADMIN",
+	"CAP_SYS_BOOT",
+	"CAP_SYS_NICE",
+	"CAP_SYS_RESOURCE",
+	"CAP_SYS_TIME",
+	"CAP_SYS_TTY_CONFIG",
+	"CAP_MKNOD",
+	"CAP_LEASE",
+	"CAP_AUDIT_WRITE",
+	"CAP_AUDIT_CONTROL",
+	"CAP_SETFCAP",
+	"CAP_MAC_OVERRIDE",
+	"CAP_MAC_ADMIN",
+	"CAP_SYSLOG",
+	"CAP_WAKE_ALARM",
+	"CAP_BLOCK_SUSPEND",
+	"CAP_AUDIT_READ",
+	"CAP_PERFMON",
+	"CAP_BPF",
+	"CAP_CHECKPOINT_RESTORE",
+}
+
+// Has returns true if the set holds the capability, such as
+// unix.CAP_SYS_ADMIN.
+func (c CapabilitySet) Has(capability int) bool {
+	return capability >= 0 && capability < 64 && c&(1<<uint(capability)) != 0
+}
+
+// Names returns the names of the capabilities of the set, such as
+// CAP_SYS_ADMIN. The capabilities unknown to this package are named by
+// their number, as CAP_41.
+func (c CapabilitySet) Names() []string {
+	names := []string{}
+	for i := 0; i < 64; i++ {
+		if !c.Has(i) {
+			continue
+		}
+		if i < len(capabilityNames) {
+			names = append(names, capabilityNames[i])
+		} else {
+			names = append(names, fmt.Sprintf("CAP_%d", i))
+		}
+	}
+	return names
+}
+
+func (c CapabilitySet) String() string {
+	return strings.Join(c.Names(), ",")
+}
+
+// SeccompMode is the seccomp mode of a process, see seccomp(2).
+type SeccompMode int32
+
+const (
+	SeccompDisabled SeccompMode = 0
+	// SeccompStrict only allows read, write, _exit and sigreturn.
+	SeccompStrict SeccompMode = 1
+	// SeccompFilter filters the system calls with BPF programs.
+	SeccompFilter SeccompMode = 2
+)
+
+func (m SeccompMode) String() string {
+	switch m {
+	case SeccompDisabled:
+		return "disabled"
+	case SeccompStrict:
+		return "strict"
+	case SeccompFilter:
+		return "filter"
+	}
+	return fmt.Sprintf("SeccompMode(%d)", int32(m))
+}
+
+// SecurityContextStat is the privilege of a process: its inheritable,
+// permitted, effective, bounding and ambient capability sets, its seccomp
+// mode, the no_new_privs flag, the pid of the process tracing it if any, and
+// its label of the Linux Security Module, such as SELinux or AppArmor.
+type SecurityContextStat struct {
+	CapInh     CapabilitySet `json:"capInh"`
+	CapPrm     CapabilitySet `json:"capPrm"`
+	CapEff     CapabilitySet `json:"capEff"`
+	CapBnd     CapabilitySet `json:"capBnd"`
+	CapAmb     CapabilitySet `json:"capAmb"`
+	Seccomp    SeccompMode   `json:"seccomp"`
+	NoNewPrivs bool          `json:"noNewPrivs"`
+	TracerPid  int32         `json:"tracerPid"`
+	// Label is empty when no security module gives labels to processes.
+	Label string `json:"label"`
+}
+
+func (s SecurityContextStat) String() string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
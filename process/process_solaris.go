@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/shirou/gopsutil/v4/cgroup"
 	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/shirou/gopsutil/v4/internal/common"
 	"github.com/shirou/gopsutil/v4/net"
@@ -29,6 +30,8 @@ type MemoryMapsStat struct {
 
 type MemoryInfoExStat struct{}
 
+type MemoryInfoStatusStat struct{}
+
 func pidsWithContext(ctx context.Context) ([]int32, error) {
 	return readPidsFromDir(common.HostProcWithContext(ctx))
 }
@@ -108,6 +111,10 @@ func (*Process) GroupsWithContext(_ context.Context) ([]uint32, error) {
 	return nil, common.ErrNotImplementedError
 }
 
+func (*Process) SecurityContextWithContext(_ context.Context) (*SecurityContextStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
 func (*Process) TerminalWithContext(_ context.Context) (string, error) {
 	return "", common.ErrNotImplementedError
 }
@@ -120,6 +127,18 @@ func (*Process) IOniceWithContext(_ context.Context) (int32, error) {
 	return 0, common.ErrNotImplementedError
 }
 
+func (*Process) SchedulerWithContext(_ context.Context) (*SchedulerStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*Process) IOPriorityWithContext(_ context.Context) (*IOPriorityStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*Process) SetIOniceWithContext(_ context.Context, _ IOPriorityClass, _ int32) error {
+	return common.ErrNotImplementedError
+}
+
 func (*Process) RlimitWithContext(_ context.Context) ([]RlimitStat, error) {
 	return nil, common.ErrNotImplementedError
 }
@@ -128,6 +147,35 @@ func (*Process) RlimitUsageWithContext(_ context.Context, _ bool) ([]RlimitStat,
 	return nil, common.ErrNotImplementedError
 }
 
+func (*Process) SetRlimitWithContext(_ context.Context, _ int32, _, _ uint64) (*RlimitStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*Process) SetNiceWithContext(_ context.Context, _ int32) error {
+	return common.ErrNotImplementedError
+}
+
+func (*Process) SetOOMScoreAdjWithContext(_ context.Context, _ int32) error {
+	return common.ErrNotImplementedError
+}
+
+func (*Process) OpenPidfdWithContext(_ context.Context) error {
+	return common.ErrNotImplementedError
+}
+
+// ClosePidfd releases the pidfd opened by OpenPidfd.
+func (*Process) ClosePidfd() error {
+	return common.ErrNotImplementedError
+}
+
+func (p *Process) WaitWithContext(ctx context.Context) error {
+	return p.waitPollWithContext(ctx)
+}
+
+func (*Process) sendSignalPidfd(_ context.Context, _ Signal) (bool, error) {
+	return false, nil
+}
+
 func (*Process) IOCountersWithContext(_ context.Context) (*IOCountersStat, error) {
 	return nil, common.ErrNotImplementedError
 }
@@ -153,18 +201,42 @@ func (*Process) TimesWithContext(_ context.Context) (*cpu.TimesStat, error) {
 	return nil, common.ErrNotImplementedError
 }
 
+func (*Process) ThreadsInfoWithContext(_ context.Context) ([]ThreadInfoStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
 func (*Process) CPUAffinityWithContext(_ context.Context) ([]int32, error) {
 	return nil, common.ErrNotImplementedError
 }
 
+func (*Process) SetCPUAffinityWithContext(_ context.Context, _ []int32) error {
+	return common.ErrNotImplementedError
+}
+
+func (*Process) ThreadCPUAffinityWithContext(_ context.Context, _ int32) ([]int32, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*Process) SetThreadCPUAffinityWithContext(_ context.Context, _ int32, _ []int32) error {
+	return common.ErrNotImplementedError
+}
+
 func (*Process) MemoryInfoWithContext(_ context.Context) (*MemoryInfoStat, error) {
 	return nil, common.ErrNotImplementedError
 }
 
+func (*Process) MemoryFullInfoWithContext(_ context.Context) (*MemoryFullInfoStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
 func (*Process) MemoryInfoExWithContext(_ context.Context) (*MemoryInfoExStat, error) {
 	return nil, common.ErrNotImplementedError
 }
 
+func (*Process) MemoryInfoStatusWithContext(_ context.Context) (*MemoryInfoStatusStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
 func (*Process) PageFaultsWithContext(_ context.Context) (*PageFaultsStat, error) {
 	return nil, common.ErrNotImplementedError
 }
@@ -177,6 +249,18 @@ func (*Process) OpenFilesWithContext(_ context.Context) ([]OpenFilesStat, error)
 	return nil, common.ErrNotImplementedError
 }
 
+func (*Process) FileDescriptorsWithContext(_ context.Context) ([]FileDescriptorStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*Process) NamespacesWithContext(_ context.Context) (*NamespacesStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*Process) CgroupsWithContext(_ context.Context) ([]cgroup.Membership, error) {
+	return nil, common.ErrNotImplementedError
+}
+
 func (*Process) ConnectionsWithContext(_ context.Context) ([]net.ConnectionStat, error) {
 	return nil, common.ErrNotImplementedError
 }
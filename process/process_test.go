@@ -4,6 +4,7 @@ package process
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -24,6 +25,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/shirou/gopsutil/v4/internal/common"
 )
 
@@ -563,6 +565,153 @@ func TestChildren(t *testing.T) {
 	assert.Truef(t, found, "could not find child %d", cmd.Process.Pid)
 }
 
+func TestTree(t *testing.T) {
+	ctx := context.Background()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "ping", "localhost", "-n", "4")
+	} else {
+		cmd = exec.CommandContext(ctx, "sleep", "3")
+	}
+	require.NoError(t, cmd.Start())
+	time.Sleep(100 * time.Millisecond)
+
+	tree, err := NewTreeWithContext(ctx)
+	if errors.Is(err, common.ErrNotImplementedError) {
+		t.Skip("not implemented")
+	}
+	require.NoError(t, err)
+
+	self := int32(os.Getpid())
+	children, err := tree.Children(self)
+	require.NoError(t, err)
+	found := false
+	for _, child := range children {
+		if child.Pid == int32(cmd.Process.Pid) {
+			found = true
+			break
+		}
+	}
+	assert.Truef(t, found, "could not find child %d", cmd.Process.Pid)
+
+	ancestors, err := tree.Ancestors(int32(cmd.Process.Pid))
+	require.NoError(t, err)
+	require.NotEmpty(t, ancestors)
+	assert.Equal(t, self, ancestors[0].Pid)
+
+	node, err := tree.Node(self)
+	require.NoError(t, err)
+	assert.NotZero(t, node.RSS)
+	mem, err := tree.SubtreeMemory(self)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, mem.RSS, node.RSS)
+}
+
+func TestProcessesInfo(t *testing.T) {
+	ctx := context.Background()
+	infos, err := ProcessesInfoWithContext(ctx, []Attr{AttrName, AttrPpid, AttrCreateTime, AttrMemoryInfo})
+	if errors.Is(err, common.ErrNotImplementedError) {
+		t.Skip("not implemented")
+	}
+	require.NoError(t, err)
+	require.NotEmpty(t, infos)
+
+	self := int32(os.Getpid())
+	var info *ProcessInfo
+	for i := range infos {
+		if infos[i].Pid == self {
+			info = &infos[i]
+			break
+		}
+	}
+	require.NotNilf(t, info, "could not find self %d", self)
+
+	p := testGetProcess()
+	name, err := p.NameWithContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, name, info.Name)
+	assert.Equal(t, int32(os.Getppid()), info.Ppid)
+	createTime, err := p.CreateTimeWithContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, createTime, info.CreateTime)
+	require.NotNil(t, info.MemoryInfo)
+	assert.NotZero(t, info.MemoryInfo.RSS)
+	// not requested
+	assert.Nil(t, info.CPUTimes)
+	assert.Nil(t, info.Cmdline)
+
+	_, err = ProcessesInfoWithContext(ctx, []Attr{"unknown"})
+	require.Error(t, err)
+}
+
+func TestTreeLookups(t *testing.T) {
+	tree := newTree([]*TreeNode{
+		{Pid: 1, Ppid: 0, RSS: 10, CPUTimes: &cpu.TimesStat{User: 1}},
+		{Pid: 20, Ppid: 1, RSS: 20, CPUTimes: &cpu.TimesStat{User: 2, System: 1}},
+		{Pid: 30, Ppid: 20, RSS: 30},
+		{Pid: 25, Ppid: 20, RSS: 40, CPUTimes: &cpu.TimesStat{System: 4}},
+		{Pid: 2, Ppid: 0},
+		// a loop, not reachable from any root
+		{Pid: 40, Ppid: 41},
+		{Pid: 41, Ppid: 40},
+	})
+
+	pids := func(procs []*Process) []int32 {
+		ret := []int32{}
+		for _, p := range procs {
+			ret = append(ret, p.Pid)
+		}
+		return ret
+	}
+
+	assert.Equal(t, []int32{1, 2, 20, 25, 30, 40, 41}, tree.Pids())
+	roots := tree.Roots()
+	require.Len(t, roots, 3)
+	assert.Equal(t, int32(1), roots[0].Pid)
+	assert.Equal(t, int32(2), roots[1].Pid)
+	assert.Equal(t, int32(40), roots[2].Pid)
+
+	parent, err := tree.Parent(30)
+	require.NoError(t, err)
+	assert.Equal(t, int32(20), parent.Pid)
+	_, err = tree.Parent(1)
+	require.ErrorIs(t, err, ErrorProcessNotRunning)
+
+	children, err := tree.Children(20)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{25, 30}, pids(children))
+
+	descendants, err := tree.Descendants(1)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{20, 25, 30}, pids(descendants))
+
+	ancestors, err := tree.Ancestors(30)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{20, 1}, pids(ancestors))
+	ancestors, err = tree.Ancestors(41)
+	require.NoError(t, err)
+	assert.Equal(t, []int32{40}, pids(ancestors))
+
+	times, err := tree.SubtreeTimes(20)
+	require.NoError(t, err)
+	assert.InDelta(t, 2.0, times.User, 0.001)
+	assert.InDelta(t, 5.0, times.System, 0.001)
+
+	mem, err := tree.SubtreeMemory(1)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(100), mem.RSS)
+
+	_, err = tree.Node(99)
+	require.ErrorIs(t, err, ErrorProcessNotRunning)
+
+	var rendered []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(tree.String()), &rendered))
+	require.Len(t, rendered, 3)
+	assert.InDelta(t, 1, rendered[0]["pid"], 0)
+	assert.Len(t, rendered[0]["children"], 1)
+}
+
 func TestUsername(t *testing.T) {
 	myPid := os.Getpid()
 	currentUser, _ := user.Current()
@@ -663,6 +812,105 @@ func TestKill(t *testing.T) {
 	cmd.Wait()
 }
 
+func TestWait(t *testing.T) {
+	ctx := context.Background()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "ping", "localhost", "-n", "2")
+	} else {
+		cmd = exec.CommandContext(ctx, "sleep", "1")
+	}
+	require.NoError(t, cmd.Start())
+	p, err := NewProcess(int32(cmd.Process.Pid))
+	if errors.Is(err, common.ErrNotImplementedError) {
+		t.Skip("not implemented")
+	}
+	require.NoError(t, err)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	err = p.WaitWithContext(timeoutCtx)
+	if errors.Is(err, common.ErrNotImplementedError) {
+		t.Skip("not implemented")
+	}
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	require.NoError(t, p.WaitWithContext(waitCtx))
+	cmd.Wait()
+}
+
+func TestWatcher(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	w, err := NewWatcherWithContext(ctx, WatchOptions{Poll: true, Interval: 50 * time.Millisecond})
+	if errors.Is(err, common.ErrNotImplementedError) {
+		t.Skip("not implemented")
+	}
+	require.NoError(t, err)
+	defer w.Close()
+	assert.False(t, w.ProcConnector())
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "ping", "localhost", "-n", "2")
+	} else {
+		cmd = exec.CommandContext(ctx, "sleep", "1")
+	}
+	require.NoError(t, cmd.Start())
+	pid := int32(cmd.Process.Pid)
+	go cmd.Wait()
+
+	var types []EventType
+	for e := range w.Events() {
+		if e.Pid != pid {
+			continue
+		}
+		types = append(types, e.Type)
+		if e.Type == EventStart {
+			assert.Equal(t, int32(os.Getpid()), e.Ppid)
+		}
+		if e.Type == EventExit {
+			break
+		}
+	}
+	require.NoError(t, ctx.Err())
+	assert.Equal(t, []EventType{EventStart, EventExit}, types)
+}
+
+func TestWatcherDiff(t *testing.T) {
+	w := &Watcher{
+		opts: WatchOptions{Comm: true},
+		known: map[int32]*TreeNode{
+			1:  {Pid: 1, Name: "init", CreateTime: 10},
+			20: {Pid: 20, Ppid: 1, Name: "old", CreateTime: 20},
+			30: {Pid: 30, Ppid: 1, Name: "reused", CreateTime: 30},
+			40: {Pid: 40, Ppid: 1, Name: "before", CreateTime: 40},
+		},
+	}
+	events := w.diff([]*TreeNode{
+		{Pid: 1, Name: "init", CreateTime: 10},
+		{Pid: 30, Ppid: 1, Name: "reused", CreateTime: 35},
+		{Pid: 40, Ppid: 1, Name: "after", CreateTime: 40},
+		{Pid: 50, Ppid: 1, Name: "new", CreateTime: 50},
+	})
+	assert.Equal(t, []Event{
+		{Type: EventExit, Pid: 20, Ppid: 1, Name: "old", CreateTime: 20},
+		{Type: EventExit, Pid: 30, Ppid: 1, Name: "reused", CreateTime: 30},
+		{Type: EventStart, Pid: 30, Ppid: 1, Name: "reused", CreateTime: 35},
+		{Type: EventComm, Pid: 40, Ppid: 1, Name: "after", CreateTime: 40},
+		{Type: EventStart, Pid: 50, Ppid: 1, Name: "new", CreateTime: 50},
+	}, events)
+	assert.Len(t, w.known, 4)
+	assert.Empty(t, w.diff([]*TreeNode{
+		{Pid: 1, Name: "init", CreateTime: 10},
+		{Pid: 30, Ppid: 1, Name: "reused", CreateTime: 35},
+		{Pid: 40, Ppid: 1, Name: "after", CreateTime: 40},
+		{Pid: 50, Ppid: 1, Name: "new", CreateTime: 50},
+	}))
+}
+
 func TestIsRunning(t *testing.T) {
 	ctx := context.Background()
 	var cmd *exec.Cmd
@@ -803,3 +1051,27 @@ func BenchmarkProcesses(b *testing.B) {
 		require.NotEmpty(b, ps)
 	}
 }
+
+func TestQuery(t *testing.T) {
+	p := testGetProcess()
+	name, err := p.Name()
+	require.NoError(t, err)
+
+	procs, err := Query(Filter{Name: name, Ppids: []int32{int32(os.Getppid())}})
+	if errors.Is(err, common.ErrNotImplementedError) {
+		t.Skip("not implemented")
+	}
+	require.NoError(t, err)
+	pids := make([]int32, 0, len(procs))
+	for _, proc := range procs {
+		pids = append(pids, proc.Pid)
+	}
+	assert.Contains(t, pids, p.Pid)
+
+	procs, err = Query(Filter{Name: name, Ppids: []int32{p.Pid}})
+	require.NoError(t, err)
+	assert.Empty(t, procs)
+
+	_, err = Query(Filter{Name: "["})
+	require.Error(t, err)
+}
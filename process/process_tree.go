@@ -0,0 +1,291 @@
+// SPDX-License-Identifier: BSD-3-Clause
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+)
+
+// TreeNode is a process of a Tree, with the values read when the snapshot
+// was taken. On Linux Name is the comm of /proc/<pid>/stat, truncated by the
+// kernel to 15 characters, unlike Process.Name and ProcessInfo.Name which
+// are extended from the command line.
+type TreeNode struct {
+	Pid        int32          `json:"pid"`
+	Ppid       int32          `json:"ppid"`
+	Name       string         `json:"name"`
+	CreateTime int64          `json:"createTime"`
+	CPUTimes   *cpu.TimesStat `json:"cpuTimes"`
+	RSS        uint64         `json:"rss"` // bytes
+	VMS        uint64         `json:"vms"` // bytes
+	Children   []*TreeNode    `json:"children"`
+}
+
+func (n TreeNode) String() string {
+	s, _ := json.Marshal(n)
+	return string(s)
+}
+
+// Tree is a snapshot of all processes and their parent/child relationship.
+// It is not updated after its creation, processes started later are not
+// found and processes which exited are still returned.
+type Tree struct {
+	nodes map[int32]*TreeNode
+	roots []*TreeNode
+}
+
+// NewTree reads all processes at once and returns their tree.
+func NewTree() (*Tree, error) {
+	return NewTreeWithContext(context.Background())
+}
+
+func NewTreeWithContext(ctx context.Context) (*Tree, error) {
+	nodes, err := treeNodesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newTree(nodes), nil
+}
+
+func newTree(nodes []*TreeNode) *Tree {
+	t := &Tree{
+		nodes: make(map[int32]*TreeNode, len(nodes)),
+	}
+	for _, n := range nodes {
+		t.nodes[n.Pid] = n
+	}
+	for _, n := range nodes {
+		parent, ok := t.nodes[n.Ppid]
+		if !ok || n.Ppid == n.Pid {
+			t.roots = append(t.roots, n)
+			continue
+		}
+		parent.Children = append(parent.Children, n)
+	}
+	for _, n := range nodes {
+		sortTreeNodes(n.Children)
+	}
+
+	// a pid reused while reading the processes may create a loop, which is
+	// not reachable from any root. Break it to keep the tree walkable.
+	reachable := make(map[int32]bool, len(nodes))
+	for _, r := range t.roots {
+		walkTreeNode(r, func(n *TreeNode) { reachable[n.Pid] = true })
+	}
+	sortTreeNodes(nodes)
+	for _, n := range nodes {
+		if reachable[n.Pid] {
+			continue
+		}
+		parent := t.nodes[n.Ppid]
+		for i, c := range parent.Children {
+			if c == n {
+				parent.Children = append(parent.Children[:i], parent.Children[i+1:]...)
+				break
+			}
+		}
+		t.roots = append(t.roots, n)
+		walkTreeNode(n, func(n *TreeNode) { reachable[n.Pid] = true })
+	}
+	sortTreeNodes(t.roots)
+	return t
+}
+
+func sortTreeNodes(nodes []*TreeNode) {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Pid < nodes[j].Pid })
+}
+
+// genericTreeNodesWithContext builds the nodes of a Tree with the Process
+// methods, for platforms without a faster way to read all processes.
+func genericTreeNodesWithContext(ctx context.Context) ([]*TreeNode, error) {
+	procs, err := ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*TreeNode, 0, len(procs))
+	for _, p := range procs {
+		ppid, err := p.PpidWithContext(ctx)
+		if err != nil {
+			// the process may have exited
+			continue
+		}
+		n := &TreeNode{
+			Pid:  p.Pid,
+			Ppid: ppid,
+		}
+		n.Name, _ = p.NameWithContext(ctx)
+		n.CreateTime, _ = p.CreateTimeWithContext(ctx)
+		n.CPUTimes, _ = p.TimesWithContext(ctx)
+		if mem, err := p.MemoryInfoWithContext(ctx); err == nil {
+			n.RSS = mem.RSS
+			n.VMS = mem.VMS
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
+
+// Roots returns the processes whose parent is not in the tree, such as
+// init or kthreadd on Linux.
+func (t *Tree) Roots() []*TreeNode {
+	return t.roots
+}
+
+// Pids returns the pids of all processes of the tree in ascending order.
+func (t *Tree) Pids() []int32 {
+	ret := make([]int32, 0, len(t.nodes))
+	for pid := range t.nodes {
+		ret = append(ret, pid)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i] < ret[j] })
+	return ret
+}
+
+// Node returns the snapshot of a process.
+func (t *Tree) Node(pid int32) (*TreeNode, error) {
+	n, ok := t.nodes[pid]
+	if !ok {
+		return nil, ErrorProcessNotRunning
+	}
+	return n, nil
+}
+
+// Process returns a Process of the tree. Its create time is taken from the
+// snapshot, so IsRunning detects if the pid has been reused since.
+func (t *Tree) Process(pid int32) (*Process, error) {
+	n, err := t.Node(pid)
+	if err != nil {
+		return nil, err
+	}
+	return n.process(), nil
+}
+
+func (n *TreeNode) process() *Process {
+	return &Process{
+		Pid:        n.Pid,
+		createTime: n.CreateTime,
+	}
+}
+
+// Parent returns the parent of a process, or ErrorProcessNotRunning if the
+// process or its parent is not in the tree.
+func (t *Tree) Parent(pid int32) (*Process, error) {
+	n, err := t.Node(pid)
+	if err != nil {
+		return nil, err
+	}
+	parent, ok := t.nodes[n.Ppid]
+	if !ok || n.Ppid == n.Pid {
+		return nil, ErrorProcessNotRunning
+	}
+	return parent.process(), nil
+}
+
+// Children returns the direct children of a process ordered by pid.
+func (t *Tree) Children(pid int32) ([]*Process, error) {
+	n, err := t.Node(pid)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Process, 0, len(n.Children))
+	for _, c := range n.Children {
+		ret = append(ret, c.process())
+	}
+	return ret, nil
+}
+
+// Descendants returns all the descendants of a process, depth first.
+func (t *Tree) Descendants(pid int32) ([]*Process, error) {
+	n, err := t.Node(pid)
+	if err != nil {
+		return nil, err
+	}
+	var ret []*Process
+	walkTreeNode(n, func(d *TreeNode) {
+		if d != n {
+			ret = append(ret, d.process())
+		}
+	})
+	return ret, nil
+}
+
+// Ancestors returns the parent of a process, its grand parent, and so on up
+// to the root of the tree.
+func (t *Tree) Ancestors(pid int32) ([]*Process, error) {
+	n, err := t.Node(pid)
+	if err != nil {
+		return nil, err
+	}
+	var ret []*Process
+	// guard against a loop, which a pid reused during the snapshot may cause
+	seen := map[int32]bool{n.Pid: true}
+	for {
+		parent, ok := t.nodes[n.Ppid]
+		if !ok || seen[parent.Pid] {
+			break
+		}
+		seen[parent.Pid] = true
+		ret = append(ret, parent.process())
+		n = parent
+	}
+	return ret, nil
+}
+
+// SubtreeTimes returns the sum of the CPU times of a process and all its
+// descendants.
+func (t *Tree) SubtreeTimes(pid int32) (*cpu.TimesStat, error) {
+	n, err := t.Node(pid)
+	if err != nil {
+		return nil, err
+	}
+	ret := &cpu.TimesStat{CPU: "cpu"}
+	walkTreeNode(n, func(d *TreeNode) {
+		if d.CPUTimes == nil {
+			return
+		}
+		ret.User += d.CPUTimes.User
+		ret.System += d.CPUTimes.System
+		ret.Iowait += d.CPUTimes.Iowait
+	})
+	return ret, nil
+}
+
+// SubtreeMemory returns the sum of RSS and VMS of a process and all its
+// descendants. Shared memory is counted once per process.
+func (t *Tree) SubtreeMemory(pid int32) (*MemoryInfoStat, error) {
+	n, err := t.Node(pid)
+	if err != nil {
+		return nil, err
+	}
+	ret := &MemoryInfoStat{}
+	walkTreeNode(n, func(d *TreeNode) {
+		ret.RSS += d.RSS
+		ret.VMS += d.VMS
+	})
+	return ret, nil
+}
+
+func walkTreeNode(n *TreeNode, fn func(*TreeNode)) {
+	fn(n)
+	for _, c := range n.Children {
+		walkTreeNode(c, fn)
+	}
+}
+
+// MarshalJSON renders the tree as the list of its roots with their nested
+// children.
+func (t *Tree) MarshalJSON() ([]byte, error) {
+	roots := t.roots
+	if roots == nil {
+		roots = []*TreeNode{}
+	}
+	return json.Marshal(roots)
+}
+
+func (t *Tree) String() string {
+	s, _ := json.Marshal(t)
+	return string(s)
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//go:build linux
+
+package process
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/internal/common"
+)
+
+// treeNodesWithContext reads /proc/<pid>/stat of every process once, which
+// has all the values of a TreeNode.
+func treeNodesWithContext(ctx context.Context) ([]*TreeNode, error) {
+	pids, err := pidsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bootTime, _ := bootTimeWithContext(ctx, enableBootTimeCache)
+
+	nodes := make([]*TreeNode, 0, len(pids))
+	for _, pid := range pids {
+		contents, err := os.ReadFile(common.HostProcWithContext(ctx, strconv.Itoa(int(pid)), "stat"))
+		if err != nil || len(contents) == 0 {
+			// the process may have exited
+			continue
+		}
+		n, err := parseTreeNodeStat(contents, bootTime)
+		if err != nil {
+			continue
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
+
+// parseTreeNodeStat parses the content of /proc/<pid>/stat, see `man proc`.
+func parseTreeNodeStat(contents []byte, bootTime uint64) (*TreeNode, error) {
+	fields := splitProcStat(contents)
+	if len(fields) < 25 {
+		return nil, fmt.Errorf("malformed stat file: expected at least 25 fields, got %d", len(fields))
+	}
+	pid, err := strconv.ParseInt(fields[1], 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	ppid, err := strconv.ParseInt(fields[4], 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	utime, err := strconv.ParseFloat(fields[14], 64)
+	if err != nil {
+		return nil, err
+	}
+	stime, err := strconv.ParseFloat(fields[15], 64)
+	if err != nil {
+		return nil, err
+	}
+	startTime, err := strconv.ParseUint(fields[22], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	vsize, err := strconv.ParseUint(fields[23], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	rss, err := strconv.ParseInt(fields[24], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if rss < 0 {
+		rss = 0
+	}
+	return &TreeNode{
+		Pid:        int32(pid),
+		Ppid:       int32(ppid),
+		Name:       fields[2],
+		CreateTime: int64((startTime * 1000 / uint64(clockTicks)) + bootTime*1000),
+		CPUTimes: &cpu.TimesStat{
+			CPU:    "cpu",
+			User:   utime / float64(clockTicks),
+			System: stime / float64(clockTicks),
+		},
+		RSS: uint64(rss) * pageSize,
+		VMS: vsize,
+	}, nil
+}
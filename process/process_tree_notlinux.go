@@ -0,0 +1,12 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//go:build !linux
+
+package process
+
+import (
+	"context"
+)
+
+func treeNodesWithContext(ctx context.Context) ([]*TreeNode, error) {
+	return genericTreeNodesWithContext(ctx)
+}
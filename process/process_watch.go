@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: BSD-3-Clause
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// EventType is the kind of change of a process reported by a Watcher.
+type EventType string
+
+const (
+	// EventStart is sent when a process is created.
+	EventStart EventType = "start"
+	// EventExit is sent when a process exits.
+	EventExit EventType = "exit"
+	// EventExec is sent when a process executes another program. It is only
+	// sent with the proc connector, see WatchOptions.
+	EventExec EventType = "exec"
+	// EventComm is sent when the name of a process changes, for example by
+	// prctl(PR_SET_NAME).
+	EventComm EventType = "comm"
+)
+
+// Event is a change of a process. Name is the name of the process after the
+// change, or its last known name for EventExit. On Linux it is the comm of
+// the process, limited to 15 characters.
+type Event struct {
+	Type       EventType `json:"type"`
+	Pid        int32     `json:"pid"`
+	Ppid       int32     `json:"ppid"`
+	CreateTime int64     `json:"createTime"`
+	Name       string    `json:"name"`
+}
+
+func (e Event) String() string {
+	s, _ := json.Marshal(e)
+	return string(s)
+}
+
+// WatchOptions configures a Watcher.
+type WatchOptions struct {
+	// Interval is the time between two scans of the processes when polling.
+	// It defaults to one second.
+	Interval time.Duration
+	// Exec and Comm enable EventExec and EventComm.
+	Exec bool
+	Comm bool
+	// Poll disables the proc connector. By default on Linux, the netlink
+	// proc connector reports every event as it happens when the caller is
+	// privileged (CAP_NET_ADMIN) and lives in the initial namespaces, or
+	// else the processes are polled every Interval, which misses the
+	// processes which do not live longer than Interval.
+	Poll bool
+}
+
+// Watcher sends the start and exit of processes on the channel returned by
+// Events.
+type Watcher struct {
+	opts          WatchOptions
+	events        chan Event
+	known         map[int32]*TreeNode
+	procConnector atomic.Bool
+	cancel        context.CancelFunc
+	done          chan struct{}
+	// bootTime is read once when the proc connector starts, to compute the
+	// create time of the processes of its events.
+	bootTime uint64
+}
+
+// NewWatcher starts watching the processes, until Close is called.
+func NewWatcher(opts WatchOptions) (*Watcher, error) {
+	return NewWatcherWithContext(context.Background(), opts)
+}
+
+// NewWatcherWithContext starts watching the processes, until Close is
+// called or ctx is done.
+func NewWatcherWithContext(ctx context.Context, opts WatchOptions) (*Watcher, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	w := &Watcher{
+		opts:   opts,
+		events: make(chan Event, 64),
+		known:  make(map[int32]*TreeNode),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	// subscribe before reading the processes, to not miss any event in
+	// between
+	var conn *procConnector
+	if !opts.Poll {
+		var err error
+		conn, err = openProcConnector()
+		w.procConnector.Store(err == nil)
+	}
+	nodes, err := treeNodesWithContext(ctx)
+	if err != nil {
+		if conn != nil {
+			conn.close()
+		}
+		cancel()
+		return nil, err
+	}
+	for _, n := range nodes {
+		w.known[n.Pid] = n
+	}
+
+	go func() {
+		defer close(w.done)
+		defer close(w.events)
+		if conn != nil {
+			w.watchProcConnector(ctx, conn)
+			return
+		}
+		w.poll(ctx)
+	}()
+	return w, nil
+}
+
+// Events returns the channel of the events, which is closed when the
+// Watcher is closed.
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+// ProcConnector returns true if the events come from the proc connector,
+// false if the processes are polled. The Watcher falls back to polling if
+// reading the proc connector fails.
+func (w *Watcher) ProcConnector() bool {
+	return w.procConnector.Load()
+}
+
+// Close stops watching and closes the channel of the events.
+func (w *Watcher) Close() error {
+	w.cancel()
+	// let the watching goroutine return if it is blocked on a send
+	for range w.events {
+	}
+	<-w.done
+	return nil
+}
+
+// emit sends e, it returns false if ctx is done.
+func (w *Watcher) emit(ctx context.Context, e Event) bool {
+	select {
+	case w.events <- e:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (w *Watcher) poll(ctx context.Context) {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !w.resync(ctx) {
+			return
+		}
+	}
+}
+
+// resync reads all processes and sends the changes since the last known
+// state. It returns false if ctx is done.
+func (w *Watcher) resync(ctx context.Context) bool {
+	nodes, err := treeNodesWithContext(ctx)
+	if err != nil {
+		// try again later
+		return ctx.Err() == nil
+	}
+	for _, e := range w.diff(nodes) {
+		if !w.emit(ctx, e) {
+			return false
+		}
+	}
+	return true
+}
+
+// diff replaces the known processes by nodes and returns the events between
+// them: exits first, then starts and name changes, each ordered by pid. A
+// pid with another create time is a new process.
+func (w *Watcher) diff(nodes []*TreeNode) []Event {
+	current := make(map[int32]*TreeNode, len(nodes))
+	for _, n := range nodes {
+		current[n.Pid] = n
+	}
+
+	var exits, others []Event
+	for pid, old := range w.known {
+		if n, ok := current[pid]; !ok || n.CreateTime != old.CreateTime {
+			exits = append(exits, treeNodeEvent(EventExit, old))
+		}
+	}
+	for _, n := range nodes {
+		old, ok := w.known[n.Pid]
+		switch {
+		case !ok || old.CreateTime != n.CreateTime:
+			others = append(others, treeNodeEvent(EventStart, n))
+		case w.opts.Comm && old.Name != n.Name:
+			others = append(others, treeNodeEvent(EventComm, n))
+		}
+	}
+	w.known = current
+
+	sortEvents(exits)
+	sortEvents(others)
+	return append(exits, others...)
+}
+
+func treeNodeEvent(typ EventType, n *TreeNode) Event {
+	return Event{
+		Type:       typ,
+		Pid:        n.Pid,
+		Ppid:       n.Ppid,
+		CreateTime: n.CreateTime,
+		Name:       n.Name,
+	}
+}
+
+func sortEvents(events []Event) {
+	sort.Slice(events, func(i, j int) bool { return events[i].Pid < events[j].Pid })
+}
@@ -0,0 +1,308 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//go:build linux
+
+package process
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/shirou/gopsutil/v4/internal/common"
+)
+
+// proc connector constants from linux/connector.h and linux/cn_proc.h
+const (
+	cnIdxProc         = 1
+	cnValProc         = 1
+	cnMsgSize         = 20
+	procEventSize     = 16 // header of struct proc_event, before event_data
+	procCnMcastListen = 1
+	procCnMcastIgnore = 2
+	procEventNone     = 0x0
+	procEventFork     = 0x1
+	procEventExec     = 0x2
+	procEventComm     = 0x200
+	procEventExit     = 0x80000000
+)
+
+// errNoProcConnectorAck is returned when the kernel ignores the subscription,
+// which it does for callers outside of the initial pid and user namespaces.
+var errNoProcConnectorAck = errors.New("proc connector did not acknowledge the subscription")
+
+// procConnector is a netlink socket subscribed to the proc connector.
+type procConnector struct {
+	f         *os.File
+	rc        syscall.RawConn
+	closeOnce sync.Once
+}
+
+func openProcConnector() (*procConnector, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, unix.NETLINK_CONNECTOR)
+	if err != nil {
+		return nil, err
+	}
+	if err := subscribeProcConnector(fd); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	if err := unix.SetNonblock(fd, true); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	// a non blocking file uses the runtime poller, so that closing it
+	// interrupts a pending read
+	f := os.NewFile(uintptr(fd), "proc connector")
+	rc, err := f.SyscallConn()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &procConnector{f: f, rc: rc}, nil
+}
+
+// subscribeProcConnector subscribes fd to the proc events and waits for the
+// kernel to acknowledge it.
+func subscribeProcConnector(fd int) error {
+	if err := unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: cnIdxProc}); err != nil {
+		return err
+	}
+	timeout := unix.Timeval{Sec: 1}
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &timeout); err != nil {
+		return err
+	}
+	if err := sendProcConnectorOp(fd, procCnMcastListen); err != nil {
+		return err
+	}
+
+	buf := make([]byte, os.Getpagesize())
+	for {
+		n, _, err := unix.Recvfrom(fd, buf, 0)
+		if err != nil {
+			if errors.Is(err, unix.EINTR) {
+				continue
+			}
+			if errors.Is(err, unix.EAGAIN) {
+				return errNoProcConnectorAck
+			}
+			return err
+		}
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			what, data, ok := parseProcEvent(m.Data)
+			if !ok || what != procEventNone || len(data) < 4 {
+				// an event received before the acknowledgement
+				continue
+			}
+			if errno := binary.NativeEndian.Uint32(data[0:4]); errno != 0 {
+				return unix.Errno(errno)
+			}
+			timeout = unix.Timeval{}
+			return unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &timeout)
+		}
+	}
+}
+
+// sendProcConnectorOp sends a struct cn_msg holding a enum proc_cn_mcast_op.
+func sendProcConnectorOp(fd int, op uint32) error {
+	msg := make([]byte, unix.NLMSG_HDRLEN+cnMsgSize+4)
+	binary.NativeEndian.PutUint32(msg[0:4], uint32(len(msg)))
+	binary.NativeEndian.PutUint16(msg[4:6], unix.NLMSG_DONE)
+	binary.NativeEndian.PutUint32(msg[8:12], 1)
+	cn := msg[unix.NLMSG_HDRLEN:]
+	binary.NativeEndian.PutUint32(cn[0:4], cnIdxProc)
+	binary.NativeEndian.PutUint32(cn[4:8], cnValProc)
+	binary.NativeEndian.PutUint16(cn[16:18], 4)
+	binary.NativeEndian.PutUint32(cn[cnMsgSize:], op)
+	return unix.Sendto(fd, msg, 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK})
+}
+
+// parseProcEvent returns the type and the event_data of the struct
+// proc_event in a struct cn_msg.
+func parseProcEvent(b []byte) (uint32, []byte, bool) {
+	if len(b) < cnMsgSize+procEventSize {
+		return 0, nil, false
+	}
+	if binary.NativeEndian.Uint32(b[0:4]) != cnIdxProc || binary.NativeEndian.Uint32(b[4:8]) != cnValProc {
+		return 0, nil, false
+	}
+	ev := b[cnMsgSize:]
+	return binary.NativeEndian.Uint32(ev[0:4]), ev[procEventSize:], true
+}
+
+// read reads a message sent by the kernel, messages from other senders are
+// dropped and read as 0 bytes.
+func (c *procConnector) read(buf []byte) (int, error) {
+	var n int
+	var rerr error
+	err := c.rc.Read(func(fd uintptr) bool {
+		var from unix.Sockaddr
+		n, from, rerr = unix.Recvfrom(int(fd), buf, 0)
+		if errors.Is(rerr, unix.EAGAIN) || errors.Is(rerr, unix.EINTR) {
+			return false
+		}
+		if sa, ok := from.(*unix.SockaddrNetlink); rerr == nil && (!ok || sa.Pid != 0) {
+			n = 0
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, rerr
+}
+
+// close unsubscribes and closes the socket, it interrupts a pending read.
+func (c *procConnector) close() {
+	c.closeOnce.Do(func() {
+		c.rc.Control(func(fd uintptr) {
+			sendProcConnectorOp(int(fd), procCnMcastIgnore)
+		})
+		c.f.Close()
+	})
+}
+
+func (w *Watcher) watchProcConnector(ctx context.Context, c *procConnector) {
+	defer c.close()
+	w.bootTime, _ = bootTimeWithContext(ctx, enableBootTimeCache)
+	go func() {
+		<-ctx.Done()
+		c.close()
+	}()
+
+	buf := make([]byte, os.Getpagesize())
+	for {
+		n, err := c.read(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if errors.Is(err, unix.ENOBUFS) {
+				// events were dropped while the receiver was too slow
+				if !w.resync(ctx) {
+					return
+				}
+				continue
+			}
+			c.close()
+			w.procConnector.Store(false)
+			w.poll(ctx)
+			return
+		}
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			continue
+		}
+		for _, m := range msgs {
+			what, data, ok := parseProcEvent(m.Data)
+			if !ok {
+				continue
+			}
+			e, ok := w.procEvent(ctx, what, data)
+			if !ok {
+				continue
+			}
+			if !w.emit(ctx, e) {
+				return
+			}
+		}
+	}
+}
+
+// procEvent updates the known processes with a proc connector event, and
+// returns the Event to send if any. Events of threads are skipped.
+func (w *Watcher) procEvent(ctx context.Context, what uint32, data []byte) (Event, bool) {
+	u32 := func(i int) int32 {
+		return int32(binary.NativeEndian.Uint32(data[i*4 : i*4+4]))
+	}
+	switch what {
+	case procEventFork:
+		if len(data) < 16 {
+			return Event{}, false
+		}
+		ppid, pid, tgid := u32(1), u32(2), u32(3)
+		if pid != tgid {
+			return Event{}, false
+		}
+		n, err := readTreeNodeWithContext(ctx, pid, w.bootTime)
+		if err != nil {
+			// the process already exited, it still had the name of its
+			// parent
+			n = &TreeNode{Pid: pid, Ppid: ppid}
+			if parent, ok := w.known[ppid]; ok {
+				n.Name = parent.Name
+			}
+		}
+		if old, ok := w.known[pid]; ok && old.CreateTime == n.CreateTime {
+			// already found when the Watcher was created
+			return Event{}, false
+		}
+		w.known[pid] = n
+		return treeNodeEvent(EventStart, n), true
+	case procEventExec:
+		if len(data) < 8 {
+			return Event{}, false
+		}
+		pid, tgid := u32(0), u32(1)
+		if pid != tgid {
+			return Event{}, false
+		}
+		n, err := readTreeNodeWithContext(ctx, pid, w.bootTime)
+		if err != nil {
+			old, ok := w.known[pid]
+			if !ok {
+				return Event{}, false
+			}
+			n = old
+		}
+		w.known[pid] = n
+		return treeNodeEvent(EventExec, n), w.opts.Exec
+	case procEventComm:
+		if len(data) < 24 {
+			return Event{}, false
+		}
+		pid, tgid := u32(0), u32(1)
+		n, ok := w.known[pid]
+		if pid != tgid || !ok {
+			return Event{}, false
+		}
+		comm := data[8:24]
+		if i := bytes.IndexByte(comm, 0); i >= 0 {
+			comm = comm[:i]
+		}
+		n.Name = string(comm)
+		return treeNodeEvent(EventComm, n), w.opts.Comm
+	case procEventExit:
+		if len(data) < 8 {
+			return Event{}, false
+		}
+		pid, tgid := u32(0), u32(1)
+		n, ok := w.known[pid]
+		if pid != tgid || !ok {
+			// a process which exited before the Watcher was created
+			return Event{}, false
+		}
+		delete(w.known, pid)
+		return treeNodeEvent(EventExit, n), true
+	}
+	return Event{}, false
+}
+
+// readTreeNodeWithContext reads /proc/<pid>/stat of a single process.
+func readTreeNodeWithContext(ctx context.Context, pid int32, bootTime uint64) (*TreeNode, error) {
+	contents, err := os.ReadFile(common.HostProcWithContext(ctx, strconv.Itoa(int(pid)), "stat"))
+	if err != nil {
+		return nil, err
+	}
+	return parseTreeNodeStat(contents, bootTime)
+}
@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: BSD-3-Clause
+//go:build !linux
+
+package process
+
+import (
+	"context"
+
+	"github.com/shirou/gopsutil/v4/internal/common"
+)
+
+// procConnector is only available on Linux.
+type procConnector struct{}
+
+func openProcConnector() (*procConnector, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*procConnector) close() {}
+
+func (w *Watcher) watchProcConnector(ctx context.Context, _ *procConnector) {
+	w.poll(ctx)
+}
@@ -19,6 +19,7 @@ import (
 
 	"golang.org/x/sys/windows"
 
+	"github.com/shirou/gopsutil/v4/cgroup"
 	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/shirou/gopsutil/v4/internal/common"
 	"github.com/shirou/gopsutil/v4/net"
@@ -75,6 +76,8 @@ type systemInfo struct {
 // Memory_info_ex is different between OSes
 type MemoryInfoExStat struct{}
 
+type MemoryInfoStatusStat struct{}
+
 type MemoryMapsStat struct{}
 
 // ioCounters is an equivalent representation of IO_COUNTERS in the Windows API.
@@ -504,6 +507,10 @@ func (*Process) GroupsWithContext(_ context.Context) ([]uint32, error) {
 	return nil, common.ErrNotImplementedError
 }
 
+func (*Process) SecurityContextWithContext(_ context.Context) (*SecurityContextStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
 func (*Process) TerminalWithContext(_ context.Context) (string, error) {
 	return "", common.ErrNotImplementedError
 }
@@ -541,6 +548,18 @@ func (*Process) IOniceWithContext(_ context.Context) (int32, error) {
 	return 0, common.ErrNotImplementedError
 }
 
+func (*Process) SchedulerWithContext(_ context.Context) (*SchedulerStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*Process) IOPriorityWithContext(_ context.Context) (*IOPriorityStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*Process) SetIOniceWithContext(_ context.Context, _ IOPriorityClass, _ int32) error {
+	return common.ErrNotImplementedError
+}
+
 func (*Process) RlimitWithContext(_ context.Context) ([]RlimitStat, error) {
 	return nil, common.ErrNotImplementedError
 }
@@ -549,6 +568,31 @@ func (*Process) RlimitUsageWithContext(_ context.Context, _ bool) ([]RlimitStat,
 	return nil, common.ErrNotImplementedError
 }
 
+func (*Process) SetRlimitWithContext(_ context.Context, _ int32, _, _ uint64) (*RlimitStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*Process) SetNiceWithContext(_ context.Context, _ int32) error {
+	return common.ErrNotImplementedError
+}
+
+func (*Process) SetOOMScoreAdjWithContext(_ context.Context, _ int32) error {
+	return common.ErrNotImplementedError
+}
+
+func (*Process) OpenPidfdWithContext(_ context.Context) error {
+	return common.ErrNotImplementedError
+}
+
+// ClosePidfd releases the pidfd opened by OpenPidfd.
+func (*Process) ClosePidfd() error {
+	return common.ErrNotImplementedError
+}
+
+func (p *Process) WaitWithContext(ctx context.Context) error {
+	return p.waitPollWithContext(ctx)
+}
+
 func (p *Process) IOCountersWithContext(_ context.Context) (*IOCountersStat, error) {
 	c, err := windows.OpenProcess(processQueryInformation, false, uint32(p.Pid))
 	if err != nil {
@@ -636,10 +680,26 @@ func (p *Process) TimesWithContext(_ context.Context) (*cpu.TimesStat, error) {
 	}, nil
 }
 
+func (*Process) ThreadsInfoWithContext(_ context.Context) ([]ThreadInfoStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
 func (*Process) CPUAffinityWithContext(_ context.Context) ([]int32, error) {
 	return nil, common.ErrNotImplementedError
 }
 
+func (*Process) SetCPUAffinityWithContext(_ context.Context, _ []int32) error {
+	return common.ErrNotImplementedError
+}
+
+func (*Process) ThreadCPUAffinityWithContext(_ context.Context, _ int32) ([]int32, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*Process) SetThreadCPUAffinityWithContext(_ context.Context, _ int32, _ []int32) error {
+	return common.ErrNotImplementedError
+}
+
 func (p *Process) MemoryInfoWithContext(_ context.Context) (*MemoryInfoStat, error) {
 	mem, err := getMemoryInfo(p.Pid)
 	if err != nil {
@@ -654,10 +714,18 @@ func (p *Process) MemoryInfoWithContext(_ context.Context) (*MemoryInfoStat, err
 	return ret, nil
 }
 
+func (*Process) MemoryFullInfoWithContext(_ context.Context) (*MemoryFullInfoStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
 func (*Process) MemoryInfoExWithContext(_ context.Context) (*MemoryInfoExStat, error) {
 	return nil, common.ErrNotImplementedError
 }
 
+func (*Process) MemoryInfoStatusWithContext(_ context.Context) (*MemoryInfoStatusStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
 func (p *Process) PageFaultsWithContext(_ context.Context) (*PageFaultsStat, error) {
 	mem, err := getMemoryInfo(p.Pid)
 	if err != nil {
@@ -793,6 +861,18 @@ func (p *Process) OpenFilesWithContext(ctx context.Context) ([]OpenFilesStat, er
 	return files, nil
 }
 
+func (*Process) FileDescriptorsWithContext(_ context.Context) ([]FileDescriptorStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*Process) NamespacesWithContext(_ context.Context) (*NamespacesStat, error) {
+	return nil, common.ErrNotImplementedError
+}
+
+func (*Process) CgroupsWithContext(_ context.Context) ([]cgroup.Membership, error) {
+	return nil, common.ErrNotImplementedError
+}
+
 func (p *Process) ConnectionsWithContext(ctx context.Context) ([]net.ConnectionStat, error) {
 	return net.ConnectionsPidWithContext(ctx, "all", p.Pid)
 }